@@ -1,29 +1,70 @@
 package routes
 
 import (
+	"context"
+	"path/filepath"
+
 	"housing-api/internal/config"
 	"housing-api/internal/controllers"
+	"housing-api/internal/health"
 	"housing-api/internal/middleware/auth"
+	"housing-api/internal/middleware/features"
+	"housing-api/internal/middleware/hotswap"
+	"housing-api/internal/middleware/httpcache"
+	"housing-api/internal/middleware/mtls"
 	"housing-api/internal/middleware/ratelimit"
+	"housing-api/internal/repositories"
+	"housing-api/internal/services"
+	"housing-api/internal/utils"
+	"housing-api/pkg/shutdown"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 // Setup configures all application routes
 func Setup(app *fiber.App, cfg *config.Config) {
-	// Apply rate limiting to all routes
-	app.Use(ratelimit.RateLimiter(cfg))
+	// Apply rate limiting to all routes; the policy is swapped when its settings are reloaded,
+	// keeping the counters in the shared storage
+	limits := ratelimit.NewStorage()
+	limiter := hotswap.New(ratelimit.RateLimiter(cfg, limits))
+	app.Use(limiter.Handle)
+	config.OnReload("rate_limit", []string{"RATE_LIMIT_WINDOW_MS", "RATE_LIMIT_MAX_REQUESTS"}, func(next *config.Config) {
+		limiter.Swap(ratelimit.RateLimiter(next, limits))
+	})
 
 	// API prefix
 	api := app.Group(cfg.APIPrefix + "/" + cfg.APIVersion)
 
-	// Initialize controllers
-	listingController, err := controllers.NewListingController()
+	// Initialize services
+	listingService, err := services.NewListingService(cfg)
+	if err != nil {
+		panic("Failed to initialize listing service: " + err.Error())
+	}
+	users, err := repositories.NewUserRepository(cfg.UsersFile)
 	if err != nil {
-		panic("Failed to initialize listing controller: " + err.Error())
+		panic("Failed to initialize user store: " + err.Error())
 	}
+	// Background saves finish before hooks run; the final flush catches anything they missed
+	shutdown.Register("user_store", func(ctx context.Context) error {
+		return users.Flush()
+	})
+	authService := services.NewAuthService(cfg, users)
 
-	authController := controllers.NewAuthController(cfg)
+	// Register readiness checks for the dependencies owned by the routes
+	health.Register("listing_data", listingService.CheckDataLoaded)
+	usersFile := cfg.UsersFile
+	if usersFile == "" {
+		usersFile = utils.GetDataFilePath("users.json")
+	}
+	health.Register("user_store", func(ctx context.Context) error {
+		return utils.CheckDirWritable(filepath.Dir(usersFile))
+	})
+
+	// Initialize controllers
+	listingController := controllers.NewListingController(listingService)
+	authController := controllers.NewAuthController(authService)
+	adminController := controllers.NewAdminController(listingService)
+	odataController := controllers.NewODataController(listingService, cfg.APIPrefix+"/"+cfg.APIVersion+"/odata")
 
 	// Auth routes (public)
 	authRoutes := api.Group("/auth")
@@ -32,21 +73,55 @@ func Setup(app *fiber.App, cfg *config.Config) {
 	authRoutes.Post("/refresh", authController.RefreshToken)
 
 	// Protected auth routes
-	authRoutes.Get("/profile", auth.JWTMiddleware(cfg), authController.GetProfile)
-	authRoutes.Post("/logout", auth.JWTMiddleware(cfg), authController.Logout)
+	authRoutes.Get("/profile", auth.JWTMiddleware(authService), authController.GetProfile)
+	authRoutes.Post("/logout", auth.JWTMiddleware(authService), authController.Logout)
 
-	// Listing routes (public)
+	// Listing routes (public), with ETags and per-route Cache-Control for conditional GETs
 	listingRoutes := api.Group("/listings")
-	listingRoutes.Get("/", listingController.GetListings)
-	listingRoutes.Get("/search", listingController.SearchListings)
-	listingRoutes.Get("/filters", listingController.GetFiltersMetadata)
-	listingRoutes.Get("/:id", listingController.GetListingByID)
+	listingRoutes.Get("/", httpcache.Conditional(cfg.CacheControlListings, listingService.LastModified), listingController.GetListings)
+	listingRoutes.Get("/search", httpcache.Conditional(cfg.CacheControlListings, listingService.LastModified), listingController.SearchListings)
+	listingRoutes.Get("/suggest", httpcache.Conditional(cfg.CacheControlListings, listingService.LastModified), listingController.GetSuggestions)
+	listingRoutes.Get("/filters", httpcache.Conditional(cfg.CacheControlFilters, listingService.LastModified), listingController.GetFiltersMetadata)
+
+	// Protected listing routes (registered before /:id so they are not shadowed by it)
+	listingRoutes.Get("/stats", auth.JWTMiddleware(authService), httpcache.Conditional(cfg.CacheControlStats, listingService.LastModified), listingController.GetListingStats)
+
+	listingRoutes.Get("/:id", httpcache.Conditional(cfg.CacheControlListing, listingService.LastModified), listingController.GetListingByID)
+
+	// RESO Web API routes (public): listings as OData Property entities
+	odataRoutes := api.Group("/odata", httpcache.Conditional(cfg.CacheControlListings, listingService.LastModified))
+	odataRoutes.Get("/", odataController.GetServiceDocument)
+	odataRoutes.Get("/$metadata", odataController.GetMetadata)
+	odataRoutes.Get("/Property", odataController.GetProperties)
+	odataRoutes.Get("/Property\\(:key\\)", odataController.GetProperty)
+
+	// Partner routes (require a verified client certificate over mutual TLS)
+	partnerRoutes := api.Group("/partner", mtls.RequireClientCert())
+	partnerRoutes.Get("/whoami", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{
+			"success": true,
+			"message": "Client certificate verified",
+			"data": fiber.Map{
+				"subject": mtls.ClientSubject(c),
+			},
+		})
+	})
+	partnerRoutes.Get("/listings", listingController.GetListings)
+	partnerRoutes.Get("/listings/:id", listingController.GetListingByID)
 
-	// Protected listing routes
-	listingRoutes.Get("/stats", auth.JWTMiddleware(cfg), listingController.GetListingStats)
+	// Admin routes (protected, restricted to ADMIN_EMAILS)
+	adminRoutes := api.Group("/admin", auth.JWTMiddleware(authService), auth.RequireAdmin(cfg))
+	adminRoutes.Post("/config/reload", adminController.ReloadConfig)
+	adminRoutes.Post("/listings/reload", adminController.ReloadListings)
+	adminRoutes.Get("/cache", adminController.GetCacheStats)
+	adminRoutes.Get("/synonyms", adminController.GetSynonyms)
+	adminRoutes.Put("/synonyms", adminController.UpdateSynonyms)
+	adminRoutes.Post("/synonyms/reload", adminController.ReloadSynonyms)
+	adminRoutes.Get("/geocoding", adminController.GetGeocodingReport)
+	adminRoutes.Post("/gazetteer/reload", adminController.ReloadGazetteer)
 
 	// Demo endpoints
-	demoRoutes := api.Group("/demo")
+	demoRoutes := api.Group("/demo", features.Require(cfg, config.FeatureDemoCredentials))
 	demoRoutes.Get("/credentials", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
 			"success": true,
@@ -58,4 +133,4 @@ func Setup(app *fiber.App, cfg *config.Config) {
 			},
 		})
 	})
-}
\ No newline at end of file
+}
@@ -1,27 +1,46 @@
 package server
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/helmet"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/gofiber/swagger"
 
 	"housing-api/api/routes"
 	"housing-api/internal/config"
+	"housing-api/internal/health"
+	"housing-api/internal/middleware/hotswap"
 	"housing-api/internal/middleware/logging"
+	metricsmw "housing-api/internal/middleware/metrics"
+	"housing-api/internal/middleware/requestid"
+	"housing-api/internal/middleware/security"
+	tracingmw "housing-api/internal/middleware/tracing"
 	"housing-api/pkg/logger"
+	"housing-api/pkg/metrics"
+	"housing-api/pkg/response"
+	"housing-api/pkg/shutdown"
+	"housing-api/pkg/tlsutil"
+	"housing-api/pkg/tracing"
+	"housing-api/pkg/version"
 )
 
-func Run() error {
+// Run starts the API server; args are command-line config overrides (e.g. --port=8080)
+func Run(args []string) error {
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.LoadWithArgs(args)
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
@@ -29,6 +48,26 @@ func Run() error {
 	// Initialize logger
 	logger.Init(cfg.LogLevel)
 
+	// Track the running configuration so SIGHUP and the admin endpoint can reload it
+	config.SetActive(cfg)
+	config.OnReload("log_level", []string{"LOG_LEVEL"}, func(next *config.Config) {
+		logger.SetLevel(next.LogLevel)
+	})
+
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Config{
+		Exporter:       cfg.TracingExporter,
+		FilePath:       cfg.TracingFile,
+		OTLPEndpoint:   cfg.TracingOTLPEndpoint,
+		SampleRatio:    cfg.TracingSampleRatio,
+		ServiceName:    "housing-api",
+		ServiceVersion: version.Version,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize tracing: %w", err)
+	}
+	shutdown.Register("tracing", shutdown.Hook(shutdownTracing))
+
 	// Create Fiber app
 	app := fiber.New(fiber.Config{
 		AppName:      "Worksquare Housing API",
@@ -39,38 +78,75 @@ func Run() error {
 				code = e.Code
 			}
 
-			return c.Status(code).JSON(fiber.Map{
-				"success": false,
-				"error": fiber.Map{
-					"code":    code,
-					"message": err.Error(),
-				},
-				"data": nil,
-			})
+			return response.Error(c, code, err.Error(), nil)
 		},
 	})
 
 	// Global middleware
 	app.Use(recover.New())
-	app.Use(helmet.New())
-	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowMethods: "GET,POST,HEAD,PUT,DELETE,PATCH",
-		AllowHeaders: "Origin,Content-Type,Accept,Authorization",
-	}))
+	app.Use(requestid.RequestID())
+	app.Use(tracingmw.Tracing())
+	app.Use(metricsmw.Metrics())
+	securityHeaders := hotswap.New(security.Headers(cfg))
+	app.Use(securityHeaders.Handle)
+	config.OnReload("security_headers", []string{
+		"SECURITY_CSP", "SECURITY_CSP_REPORT_ONLY", "SECURITY_HSTS_MAX_AGE",
+		"SECURITY_HSTS_INCLUDE_SUBDOMAINS", "SECURITY_HSTS_PRELOAD", "SECURITY_REFERRER_POLICY",
+	}, func(next *config.Config) {
+		securityHeaders.Swap(security.Headers(next))
+	})
+	corsHandler := hotswap.New(security.CORS(cfg))
+	app.Use(corsHandler.Handle)
+	config.OnReload("cors", []string{
+		"CORS_ALLOW_ORIGINS", "CORS_ALLOW_METHODS", "CORS_ALLOW_HEADERS",
+		"CORS_EXPOSE_HEADERS", "CORS_ALLOW_CREDENTIALS", "CORS_MAX_AGE",
+	}, func(next *config.Config) {
+		corsHandler.Swap(security.CORS(next))
+	})
 	app.Use(logging.RequestLogger())
 
 	// Swagger documentation
 	app.Get("/swagger/*", swagger.HandlerDefault)
 
-	// Health check endpoint
+	// Metrics endpoint, served on the main listener unless a dedicated port is configured
+	var metricsServer *http.Server
+	if cfg.MetricsEnabled {
+		if cfg.MetricsPort == "" {
+			app.Get(cfg.MetricsPath, adaptor.HTTPHandler(metrics.Handler(cfg.MetricsToken)))
+		} else {
+			mux := http.NewServeMux()
+			mux.Handle(cfg.MetricsPath, metrics.Handler(cfg.MetricsToken))
+			metricsServer = &http.Server{
+				Addr:              fmt.Sprintf(":%s", cfg.MetricsPort),
+				Handler:           mux,
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+		}
+	}
+
+	// Health check endpoints
+	health.Register("config", func(ctx context.Context) error {
+		return config.Active().Validate()
+	})
+	health.Register("signing_keys", func(ctx context.Context) error {
+		if cfg.JWTSecret == "" {
+			return errors.New("JWT signing secret is not configured")
+		}
+		return nil
+	})
+
+	app.Get("/livez", health.LivenessHandler())
+	app.Get("/readyz", health.ReadinessHandler())
 	app.Get("/health", func(c *fiber.Ctx) error {
+		build := version.Get()
 		return c.JSON(fiber.Map{
 			"success": true,
 			"message": "Worksquare Housing API is running!",
 			"data": fiber.Map{
-				"status":  "healthy",
-				"version": "1.0.0",
+				"status":     "healthy",
+				"version":    build.Version,
+				"commit":     build.Commit,
+				"build_time": build.BuildTime,
 			},
 		})
 	})
@@ -78,27 +154,172 @@ func Run() error {
 	// Setup routes
 	routes.Setup(app, cfg)
 
-	// Start server
+	// Start server; listener failures are reported back so the process exits non-zero
+	listener, err := newListener(cfg)
+	if err != nil {
+		return err
+	}
+
+	serverErr := make(chan error, 3)
 	go func() {
-		if err := app.Listen(fmt.Sprintf(":%s", cfg.Port)); err != nil {
-			log.Printf("Failed to start server: %v", err)
+		if err := app.Listener(listener); err != nil {
+			serverErr <- err
 		}
 	}()
 
+	if cfg.TLSEnabled() && cfg.HTTPRedirectPort != "" {
+		redirectApp := newRedirectApp(cfg)
+		go func() {
+			if err := redirectApp.Listen(fmt.Sprintf(":%s", cfg.HTTPRedirectPort)); err != nil {
+				serverErr <- fmt.Errorf("redirect server: %w", err)
+			}
+		}()
+		shutdown.Register("redirect_server", redirectApp.ShutdownWithContext)
+		log.Printf("↪️  Redirecting HTTP on port %s to HTTPS", cfg.HTTPRedirectPort)
+	}
+
+	if metricsServer != nil {
+		go func() {
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serverErr <- fmt.Errorf("metrics server: %w", err)
+			}
+		}()
+		shutdown.Register("metrics_server", metricsServer.Shutdown)
+		log.Printf("📈 Metrics available on port %s at %s", cfg.MetricsPort, cfg.MetricsPath)
+	}
+
+	scheme := "http"
+	if cfg.TLSEnabled() {
+		scheme = "https"
+	}
 	log.Printf("🚀 Server started on port %s", cfg.Port)
-	log.Printf("📚 Swagger documentation available at http://localhost:%s/swagger/", cfg.Port)
+	log.Printf("📚 Swagger documentation available at %s://localhost:%s/swagger/", scheme, cfg.Port)
 
-	// Wait for interrupt signal to gracefully shutdown the server
+	// Wait for interrupt signal to gracefully shutdown the server; SIGHUP reloads configuration
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case err := <-serverErr:
+			return err
+		case <-hup:
+			reloadConfig(context.Background())
+		case <-quit:
+			return gracefulShutdown(app, quit)
+		}
+	}
+}
+
+// reloadConfig re-reads configuration on SIGHUP; an invalid configuration leaves the running one in place
+func reloadConfig(ctx context.Context) {
+	result, err := config.Reload()
+	if err != nil {
+		logger.ErrorContext(ctx, "Configuration reload rejected", "error", err.Error())
+		return
+	}
+
+	logger.InfoContext(ctx, "Configuration reloaded", "applied", result.Applied, "requires_restart", result.RequiresRestart)
+	if len(result.RequiresRestart) > 0 {
+		log.Printf("♻️  Restart required to apply: %v", result.RequiresRestart)
+	}
+}
+
+// PrintConfig writes the effective configuration with secrets redacted. Invalid configuration is
+// still printed, followed by the validation error.
+func PrintConfig(w io.Writer, args []string) error {
+	cfg, loadErr := config.LoadWithArgs(args)
+	if err := cfg.Print(w); err != nil {
+		return err
+	}
+	return loadErr
+}
+
+// newListener opens the API listener, wrapping it in TLS with hot-reloaded certificates when configured
+func newListener(cfg *config.Config) (net.Listener, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", cfg.Port))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+
+	if !cfg.TLSEnabled() {
+		return listener, nil
+	}
+
+	reloader, err := tlsutil.NewCertReloader(cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSReloadInterval)
+	if err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+
+	tlsConfig, err := tlsutil.ServerConfig(reloader, cfg.TLSClientCAFile)
+	if err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+
+	return tls.NewListener(listener, tlsConfig), nil
+}
+
+// newRedirectApp builds a plain HTTP app that permanently redirects every request to HTTPS
+func newRedirectApp(cfg *config.Config) *fiber.App {
+	redirectApp := fiber.New(fiber.Config{DisableStartupMessage: true})
+	redirectApp.Use(func(c *fiber.Ctx) error {
+		host := c.Hostname()
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if cfg.Port != "443" {
+			host = net.JoinHostPort(host, cfg.Port)
+		}
+		return c.Redirect("https://"+host+c.OriginalURL(), fiber.StatusPermanentRedirect)
+	})
+	return redirectApp
+}
 
+// gracefulShutdown fails readiness, waits out the drain period, then stops the server and
+// flushes background work within the shutdown deadline, both as last reloaded. A second signal
+// skips the drain.
+func gracefulShutdown(app *fiber.App, quit <-chan os.Signal) error {
+	cfg := config.Active()
 	log.Println("🛑 Shutting down server...")
+	health.SetDraining(true)
 
-	if err := app.Shutdown(); err != nil {
-		return fmt.Errorf("server forced to shutdown: %w", err)
+	if cfg.ShutdownDrainPeriod > 0 {
+		log.Printf("⏳ Draining connections for %s", cfg.ShutdownDrainPeriod)
+		select {
+		case <-time.After(cfg.ShutdownDrainPeriod):
+		case <-quit:
+			log.Println("Second signal received, skipping drain period")
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	// Force exit if anything ignores the deadline
+	forceExit := time.AfterFunc(cfg.ShutdownTimeout+time.Second, func() {
+		log.Println("💥 Shutdown deadline exceeded, forcing exit")
+		os.Exit(1)
+	})
+	defer forceExit.Stop()
+
+	// Flush background work even when the server missed the deadline, so traces, metrics and
+	// user writes aren't lost
+	var errs []error
+	if err := app.ShutdownWithContext(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("server forced to shutdown: %w", err))
+	}
+	if err := shutdown.Run(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to flush background work: %w", err))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
 	}
 
 	log.Println("✅ Server exited")
 	return nil
-}
\ No newline at end of file
+}
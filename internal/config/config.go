@@ -1,31 +1,129 @@
 package config
 
 import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
 	"os"
-	"strconv"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// Defaults that must not be used in production
+const (
+	DefaultJWTSecret        = "super-secret-jwt-key"
+	DefaultDemoUserEmail    = "demo@worksquare.com"
+	DefaultDemoUserPassword = "demo123456"
+)
+
+// Feature flags, enabled by listing them in FEATURE_FLAGS
+const (
+	FeatureDemoCredentials = "demo_credentials"
+)
+
+// knownFeatures are the feature flags FEATURE_FLAGS may contain
+var knownFeatures = map[string]bool{
+	FeatureDemoCredentials: true,
+}
+
+// Value sources, in increasing order of precedence
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
 type Config struct {
 	// Server Configuration
 	Environment string
 	Port        string
 	Host        string
 
+	// TLS Configuration
+	TLSCertFile       string
+	TLSKeyFile        string
+	TLSClientCAFile   string
+	TLSReloadInterval time.Duration
+	HTTPRedirectPort  string
+
+	// Shutdown
+	ShutdownDrainPeriod time.Duration
+	ShutdownTimeout     time.Duration
+
 	// JWT Configuration
 	JWTSecret           string
 	JWTExpiresIn        time.Duration
 	JWTRefreshExpiresIn time.Duration
 
+	// Pagination
+	CursorSecret string
+
+	// Search
+	SearchSynonymsFile string
+	FacetPriceBuckets  []int
+
+	// Geocoding
+	GeocoderGazetteerFile string
+
 	// Rate Limiting
-	RateLimitWindowMS   time.Duration
+	RateLimitWindowMS    time.Duration
 	RateLimitMaxRequests int
 
+	// CORS
+	CORSAllowOrigins     []string
+	CORSAllowMethods     []string
+	CORSAllowHeaders     []string
+	CORSExposeHeaders    []string
+	CORSAllowCredentials bool
+	CORSMaxAge           time.Duration
+
+	// Security Headers
+	SecurityCSP                   string
+	SecurityCSPReportOnly         bool
+	SecurityHSTSMaxAge            time.Duration
+	SecurityHSTSIncludeSubdomains bool
+	SecurityHSTSPreload           bool
+	SecurityReferrerPolicy        string
+
+	// HTTP Caching
+	CacheControlListings string
+	CacheControlListing  string
+	CacheControlFilters  string
+	CacheControlStats    string
+
+	// Response Cache (0 entries disables it)
+	CacheMaxEntries int
+	CacheTTL        time.Duration
+
 	// Logging
 	LogLevel string
 
+	// Feature Flags
+	FeatureFlags []string
+
+	// Admin users, by email, allowed to manage the running service
+	AdminEmails []string
+
+	// Metrics
+	MetricsEnabled bool
+	MetricsPath    string
+	MetricsToken   string
+	MetricsPort    string
+
+	// Tracing
+	TracingExporter     string
+	TracingFile         string
+	TracingOTLPEndpoint string
+	TracingSampleRatio  float64
+
 	// API Configuration
 	APIVersion string
 	APIPrefix  string
@@ -33,50 +131,259 @@ type Config struct {
 	// Demo User Credentials
 	DemoUserEmail    string
 	DemoUserPassword string
+
+	// User Store
+	UsersFile string
+
+	// sources records where each key's effective value came from
+	sources map[string]string
+
+	// args are the command-line overrides the config was loaded with, reused on reload
+	args []string
+}
+
+// dotenvSet records the variables loadDotenv set from the .env file and the values it set
+var (
+	dotenvMu  sync.Mutex
+	dotenvSet = map[string]string{}
+)
+
+// loadDotenv copies the .env file, if any, into the environment. As with godotenv.Load, variables
+// set in the real environment take precedence; but those the file supplied follow edits to it on
+// every call, and are unset when removed from it, so a reload sees the current file. A variable
+// changed since by anything else is left alone. An unreadable file changes nothing.
+func loadDotenv() {
+	dotenvMu.Lock()
+	defer dotenvMu.Unlock()
+
+	values, err := godotenv.Read()
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+
+	for key, loaded := range dotenvSet {
+		if current, ok := os.LookupEnv(key); !ok || current != loaded {
+			delete(dotenvSet, key)
+			continue
+		}
+		if _, ok := values[key]; !ok {
+			_ = os.Unsetenv(key)
+			delete(dotenvSet, key)
+		}
+	}
+	for key, value := range values {
+		if _, ok := os.LookupEnv(key); ok {
+			if _, ours := dotenvSet[key]; !ours {
+				continue
+			}
+		}
+		_ = os.Setenv(key, value)
+		dotenvSet[key] = value
+	}
 }
 
+// Load reads configuration from defaults, the optional config file (CONFIG_FILE) and the environment
 func Load() (*Config, error) {
+	return LoadWithArgs(nil)
+}
+
+// LoadWithArgs layers defaults, the config file, the environment and command-line flags, in that
+// order, then validates the result. On failure the returned error lists every bad key; the config
+// is still returned so it can be inspected (e.g. by `config print`).
+func LoadWithArgs(args []string) (*Config, error) {
 	// Load .env file if it exists
-	_ = godotenv.Load()
-
-	cfg := &Config{
-		Environment:          getEnv("NODE_ENV", "development"),
-		Port:                 getEnv("PORT", "3000"),
-		Host:                 getEnv("HOST", "localhost"),
-		JWTSecret:           getEnv("JWT_SECRET", "super-secret-jwt-key"),
-		JWTExpiresIn:        parseDuration(getEnv("JWT_EXPIRES_IN", "24h")),
-		JWTRefreshExpiresIn: parseDuration(getEnv("JWT_REFRESH_EXPIRES_IN", "168h")), // 7 days
-		RateLimitWindowMS:   parseDuration(getEnv("RATE_LIMIT_WINDOW_MS", "3600000ms")), // 1 hour
-		RateLimitMaxRequests: parseInt(getEnv("RATE_LIMIT_MAX_REQUESTS", "100")),
-		LogLevel:            getEnv("LOG_LEVEL", "info"),
-		APIVersion:          getEnv("API_VERSION", "v1"),
-		APIPrefix:           getEnv("API_PREFIX", "/api"),
-		DemoUserEmail:       getEnv("DEMO_USER_EMAIL", "demo@worksquare.com"),
-		DemoUserPassword:    getEnv("DEMO_USER_PASSWORD", "demo123456"),
+	loadDotenv()
+
+	cfg := &Config{sources: make(map[string]string, len(settings)), args: args}
+	problems := &Error{}
+
+	// Defaults
+	for _, s := range settings {
+		cfg.set(s, s.def, SourceDefault, problems)
+	}
+
+	// Flags are parsed first to find --config, but applied last
+	flagValues, configFile, err := parseFlags(args)
+	if err != nil {
+		problems.add("flags", err.Error())
+	}
+	if configFile == "" {
+		configFile = os.Getenv("CONFIG_FILE")
+	}
+
+	var file *fileConfig
+	if configFile != "" {
+		file, err = readFile(configFile)
+		if err != nil {
+			problems.add("CONFIG_FILE", err.Error())
+		}
 	}
 
+	// The environment name decides which environment-specific values apply
+	environment := resolveEnvironment(file, flagValues)
+
+	// Built-in environment defaults
+	for key, value := range environmentDefaults[environment] {
+		if s, ok := findSetting(key); ok {
+			cfg.set(s, value, SourceDefault, problems)
+		}
+	}
+
+	// Config file, then its section for the current environment
+	if file != nil {
+		cfg.applyFile(file.values, configFile, SourceFile, problems)
+		cfg.applyFile(file.environments[environment], configFile, SourceFile+":"+environment, problems)
+	}
+
+	// Environment
+	for _, s := range settings {
+		if value := os.Getenv(s.key); value != "" {
+			cfg.set(s, value, SourceEnv, problems)
+		}
+	}
+
+	// Command-line flags
+	for _, fv := range flagValues {
+		cfg.set(fv.setting, fv.value, SourceFlag, problems)
+	}
+
+	// Semantic validation; keys that already failed to parse keep their parse error
+	var validationErr *Error
+	if errors.As(cfg.Validate(), &validationErr) {
+		for _, problem := range validationErr.Problems {
+			problems.add(problem.Key, problem.Message)
+		}
+	}
+
+	if len(problems.Problems) > 0 {
+		return cfg, problems
+	}
 	return cfg, nil
 }
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// applyFile applies values read from a config file, in key order, reporting unknown keys
+func (c *Config) applyFile(values map[string]string, path, source string, problems *Error) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s, ok := findSetting(key)
+		if !ok {
+			problems.add(key, "unknown configuration key in "+path)
+			continue
+		}
+		c.set(s, values[key], source, problems)
 	}
-	return defaultValue
 }
 
-func parseInt(s string) int {
-	i, err := strconv.Atoi(s)
-	if err != nil {
-		return 0
+// resolveEnvironment returns NODE_ENV as the flags, environment, config file or default set it
+func resolveEnvironment(file *fileConfig, flagValues []flagValue) string {
+	for _, fv := range flagValues {
+		if fv.setting.key == "NODE_ENV" {
+			return fv.value
+		}
+	}
+	if env := os.Getenv("NODE_ENV"); env != "" {
+		return env
+	}
+	if file != nil {
+		for key, value := range file.values {
+			if s, ok := findSetting(key); ok && s.key == "NODE_ENV" {
+				return value
+			}
+		}
 	}
-	return i
+	s, _ := findSetting("NODE_ENV")
+	return s.def
 }
 
-func parseDuration(s string) time.Duration {
-	d, err := time.ParseDuration(s)
-	if err != nil {
-		return 0
+// set applies a raw value to a setting, recording its source or the parse problem
+func (c *Config) set(s setting, value, source string, problems *Error) {
+	if err := s.apply(c, value); err != nil {
+		if s.secret {
+			problems.add(s.key, fmt.Sprintf("%s (from %s)", err, source))
+		} else {
+			problems.add(s.key, fmt.Sprintf("%s, got %q (from %s)", err, value, source))
+		}
+		return
 	}
-	return d
-}
\ No newline at end of file
+	c.sources[s.key] = source
+}
+
+// FeatureEnabled reports whether the named feature flag is switched on
+func (c *Config) FeatureEnabled(name string) bool {
+	for _, flag := range c.FeatureFlags {
+		if flag == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAdmin reports whether email belongs to a configured admin
+func (c *Config) IsAdmin(email string) bool {
+	for _, admin := range c.AdminEmails {
+		if strings.EqualFold(admin, email) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsProduction reports whether the service runs in production mode
+func (c *Config) IsProduction() bool {
+	return c.Environment == "production"
+}
+
+// Print writes the effective configuration and the source of each value, with secrets redacted
+func (c *Config) Print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE")
+	for _, s := range settings {
+		value := s.format(c)
+		if s.secret && value != "" {
+			value = "[REDACTED]"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", s.key, value, c.sources[s.key])
+	}
+	return tw.Flush()
+}
+
+type flagValue struct {
+	setting setting
+	value   string
+}
+
+// parseFlags parses --key=value overrides (e.g. --rate-limit-window-ms=60000) and --config
+func parseFlags(args []string) ([]flagValue, string, error) {
+	if len(args) == 0 {
+		return nil, "", nil
+	}
+
+	fs := flag.NewFlagSet("housing-api", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	configFile := fs.String("config", "", "path to a YAML or TOML config file")
+	for _, s := range settings {
+		fs.String(flagName(s.key), "", "overrides "+s.key)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, "", err
+	}
+	if fs.NArg() > 0 {
+		return nil, "", fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+
+	var values []flagValue
+	fs.Visit(func(f *flag.Flag) {
+		if s, ok := findSetting(f.Name); ok {
+			values = append(values, flagValue{setting: s, value: f.Value.String()})
+		}
+	})
+
+	return values, *configFile, nil
+}
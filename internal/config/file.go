@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// environmentsKey names the config file section holding per-environment overrides, e.g.
+//
+//	environments:
+//	  production:
+//	    cors_allow_origins: https://app.worksquare.com
+const environmentsKey = "environments"
+
+// fileConfig holds the raw string values read from a config file
+type fileConfig struct {
+	values       map[string]string
+	environments map[string]map[string]string
+}
+
+// readFile reads a YAML or TOML config file into raw string values keyed by setting name
+func readFile(path string) (*fileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	raw := map[string]interface{}{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return nil, fmt.Errorf("unsupported config file format %q (use .yaml, .yml or .toml)", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	file := &fileConfig{environments: map[string]map[string]string{}}
+
+	sections, _ := raw[environmentsKey].(map[string]interface{})
+	if _, ok := raw[environmentsKey]; ok && sections == nil {
+		return nil, fmt.Errorf("%s: must map environment names to settings", environmentsKey)
+	}
+	delete(raw, environmentsKey)
+
+	if file.values, err = flatten(raw); err != nil {
+		return nil, err
+	}
+	for name, section := range sections {
+		values, ok := section.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s.%s: must be a section of settings", environmentsKey, name)
+		}
+		if file.environments[name], err = flatten(values); err != nil {
+			return nil, fmt.Errorf("%s.%s.%w", environmentsKey, name, err)
+		}
+	}
+
+	return file, nil
+}
+
+// flatten stringifies a section of settings
+func flatten(raw map[string]interface{}) (map[string]string, error) {
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		str, err := stringify(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		values[key] = str
+	}
+	return values, nil
+}
+
+// stringify renders a decoded file value in the same form the environment would provide it
+func stringify(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			part, err := stringify(item)
+			if err != nil {
+				return "", err
+			}
+			parts[i] = part
+		}
+		return strings.Join(parts, ","), nil
+	case map[string]interface{}:
+		return "", fmt.Errorf("nested sections are not supported")
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// ReloadResult describes what a configuration reload changed
+type ReloadResult struct {
+	Applied         []string `json:"applied"`
+	RequiresRestart []string `json:"requires_restart"`
+}
+
+// reloadHook is notified when any of its keys change
+type reloadHook struct {
+	name string
+	keys []string
+	fn   func(*Config)
+}
+
+var (
+	active   atomic.Pointer[Config]
+	reloadMu sync.Mutex
+	hooksMu  sync.RWMutex
+	hooks    []*reloadHook
+)
+
+// SetActive records cfg as the running configuration that reloads are compared against
+func SetActive(cfg *Config) {
+	active.Store(cfg)
+}
+
+// Active returns the running configuration, or nil if none has been set
+func Active() *Config {
+	return active.Load()
+}
+
+// OnReload registers a named hook that receives the new configuration whenever one of keys
+// changes in a reload, replacing any hook with the same name. The returned function removes the
+// hook, unless it has since been replaced.
+func OnReload(name string, keys []string, fn func(*Config)) (remove func()) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+
+	hook := &reloadHook{name: name, keys: keys, fn: fn}
+	remove = func() {
+		hooksMu.Lock()
+		defer hooksMu.Unlock()
+		hooks = slices.DeleteFunc(hooks, func(h *reloadHook) bool { return h == hook })
+	}
+
+	for i := range hooks {
+		if hooks[i].name == name {
+			hooks[i] = hook
+			return remove
+		}
+	}
+	hooks = append(hooks, hook)
+	return remove
+}
+
+// Reload re-reads configuration with the same sources and flags as the active one. Settings that
+// can change live are swapped in atomically and their hooks run; other changed keys are reported
+// as requiring a restart and keep their running values. An invalid configuration is rejected whole.
+func Reload() (*ReloadResult, error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	current := Active()
+	if current == nil {
+		return nil, errors.New("no active configuration to reload")
+	}
+
+	fresh, err := LoadWithArgs(current.args)
+	if err != nil {
+		return nil, err
+	}
+
+	next := current.clone()
+	result := &ReloadResult{Applied: []string{}, RequiresRestart: []string{}}
+	changed := map[string]bool{}
+
+	for _, s := range settings {
+		if s.format(current) == s.format(fresh) {
+			continue
+		}
+		if !s.live {
+			result.RequiresRestart = append(result.RequiresRestart, s.key)
+			continue
+		}
+		s.copy(next, fresh)
+		next.sources[s.key] = fresh.sources[s.key]
+		result.Applied = append(result.Applied, s.key)
+		changed[s.key] = true
+	}
+
+	if len(changed) == 0 {
+		return result, nil
+	}
+
+	SetActive(next)
+
+	hooksMu.RLock()
+	defer hooksMu.RUnlock()
+	for _, hook := range hooks {
+		for _, key := range hook.keys {
+			if changed[key] {
+				hook.fn(next)
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// clone returns a copy of c that can be modified without affecting readers of c
+func (c *Config) clone() *Config {
+	next := *c
+	next.FeatureFlags = append([]string(nil), c.FeatureFlags...)
+	next.sources = make(map[string]string, len(c.sources))
+	for key, source := range c.sources {
+		next.sources[key] = source
+	}
+	return &next
+}
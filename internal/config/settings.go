@@ -0,0 +1,280 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// setting describes one configuration key: its default, how it is parsed and where it is stored
+type setting struct {
+	key    string
+	def    string
+	secret bool
+	live   bool
+	apply  func(cfg *Config, value string) error
+	format func(cfg *Config) string
+	copy   func(dst, src *Config)
+}
+
+// bind builds a setting that parses its value with parse and stores it in the field returned by field
+func bind[T any](key, def string, parse func(string) (T, error), field func(*Config) *T) setting {
+	return setting{
+		key: key,
+		def: def,
+		apply: func(cfg *Config, value string) error {
+			parsed, err := parse(value)
+			if err != nil {
+				return err
+			}
+			*field(cfg) = parsed
+			return nil
+		},
+		format: func(cfg *Config) string {
+			return formatValue(*field(cfg))
+		},
+		copy: func(dst, src *Config) {
+			*field(dst) = *field(src)
+		},
+	}
+}
+
+// secret marks a setting whose value must never be printed
+func secret(s setting) setting {
+	s.secret = true
+	return s
+}
+
+// live marks a setting that can be changed by a reload without restarting
+func live(s setting) setting {
+	s.live = true
+	return s
+}
+
+// settings lists every configuration key, in the order they are printed
+var settings = []setting{
+	// Server Configuration
+	bind("NODE_ENV", "development", parseString, func(c *Config) *string { return &c.Environment }),
+	bind("PORT", "3000", parseString, func(c *Config) *string { return &c.Port }),
+	bind("HOST", "localhost", parseString, func(c *Config) *string { return &c.Host }),
+
+	// TLS Configuration
+	bind("TLS_CERT_FILE", "", parseString, func(c *Config) *string { return &c.TLSCertFile }),
+	bind("TLS_KEY_FILE", "", parseString, func(c *Config) *string { return &c.TLSKeyFile }),
+	bind("TLS_CLIENT_CA_FILE", "", parseString, func(c *Config) *string { return &c.TLSClientCAFile }),
+	bind("TLS_RELOAD_INTERVAL", "1m", parseDuration, func(c *Config) *time.Duration { return &c.TLSReloadInterval }),
+	bind("HTTP_REDIRECT_PORT", "", parseString, func(c *Config) *string { return &c.HTTPRedirectPort }),
+
+	// Shutdown
+	live(bind("SHUTDOWN_DRAIN_PERIOD", "5s", parseDuration, func(c *Config) *time.Duration { return &c.ShutdownDrainPeriod })),
+	live(bind("SHUTDOWN_TIMEOUT", "15s", parseDuration, func(c *Config) *time.Duration { return &c.ShutdownTimeout })),
+
+	// JWT Configuration
+	secret(bind("JWT_SECRET", DefaultJWTSecret, parseString, func(c *Config) *string { return &c.JWTSecret })),
+	bind("JWT_EXPIRES_IN", "24h", parseDuration, func(c *Config) *time.Duration { return &c.JWTExpiresIn }),
+	bind("JWT_REFRESH_EXPIRES_IN", "168h", parseDuration, func(c *Config) *time.Duration { return &c.JWTRefreshExpiresIn }), // 7 days
+
+	// Pagination (cursors are signed with JWT_SECRET unless a dedicated secret is set)
+	secret(bind("PAGINATION_CURSOR_SECRET", "", parseString, func(c *Config) *string { return &c.CursorSecret })),
+
+	// Search (an empty synonyms file path means data/synonyms.json)
+	bind("SEARCH_SYNONYMS_FILE", "", parseString, func(c *Config) *string { return &c.SearchSynonymsFile }),
+	bind("FACET_PRICE_BUCKETS", "1000000,2000000,3000000,5000000", parseIntList, func(c *Config) *[]int { return &c.FacetPriceBuckets }),
+
+	// Geocoding (an empty gazetteer file path means data/gazetteer.json)
+	bind("GEOCODER_GAZETTEER_FILE", "", parseString, func(c *Config) *string { return &c.GeocoderGazetteerFile }),
+
+	// Rate Limiting
+	live(bind("RATE_LIMIT_WINDOW_MS", "3600000ms", parseMilliseconds, func(c *Config) *time.Duration { return &c.RateLimitWindowMS })), // 1 hour
+	live(bind("RATE_LIMIT_MAX_REQUESTS", "100", parseInt, func(c *Config) *int { return &c.RateLimitMaxRequests })),
+
+	// CORS
+	live(bind("CORS_ALLOW_ORIGINS", "*", parseList, func(c *Config) *[]string { return &c.CORSAllowOrigins })),
+	live(bind("CORS_ALLOW_METHODS", "GET,POST,HEAD,PUT,DELETE,PATCH", parseList, func(c *Config) *[]string { return &c.CORSAllowMethods })),
+	live(bind("CORS_ALLOW_HEADERS", "Origin,Content-Type,Accept,Authorization", parseList, func(c *Config) *[]string { return &c.CORSAllowHeaders })),
+	live(bind("CORS_EXPOSE_HEADERS", "", parseList, func(c *Config) *[]string { return &c.CORSExposeHeaders })),
+	live(bind("CORS_ALLOW_CREDENTIALS", "false", parseBool, func(c *Config) *bool { return &c.CORSAllowCredentials })),
+	live(bind("CORS_MAX_AGE", "0s", parseDuration, func(c *Config) *time.Duration { return &c.CORSMaxAge })),
+
+	// Security Headers
+	live(bind("SECURITY_CSP", "", parseString, func(c *Config) *string { return &c.SecurityCSP })),
+	live(bind("SECURITY_CSP_REPORT_ONLY", "false", parseBool, func(c *Config) *bool { return &c.SecurityCSPReportOnly })),
+	live(bind("SECURITY_HSTS_MAX_AGE", "0s", parseDuration, func(c *Config) *time.Duration { return &c.SecurityHSTSMaxAge })),
+	live(bind("SECURITY_HSTS_INCLUDE_SUBDOMAINS", "true", parseBool, func(c *Config) *bool { return &c.SecurityHSTSIncludeSubdomains })),
+	live(bind("SECURITY_HSTS_PRELOAD", "false", parseBool, func(c *Config) *bool { return &c.SecurityHSTSPreload })),
+	live(bind("SECURITY_REFERRER_POLICY", "no-referrer", parseString, func(c *Config) *string { return &c.SecurityReferrerPolicy })),
+
+	// HTTP Caching (Cache-Control per route)
+	bind("CACHE_CONTROL_LISTINGS", "public, max-age=60", parseString, func(c *Config) *string { return &c.CacheControlListings }),
+	bind("CACHE_CONTROL_LISTING", "public, max-age=300", parseString, func(c *Config) *string { return &c.CacheControlListing }),
+	bind("CACHE_CONTROL_FILTERS", "public, max-age=3600", parseString, func(c *Config) *string { return &c.CacheControlFilters }),
+	bind("CACHE_CONTROL_STATS", "private, max-age=60", parseString, func(c *Config) *string { return &c.CacheControlStats }),
+
+	// Response Cache
+	bind("CACHE_MAX_ENTRIES", "1000", parseInt, func(c *Config) *int { return &c.CacheMaxEntries }),
+	bind("CACHE_TTL", "5m", parseDuration, func(c *Config) *time.Duration { return &c.CacheTTL }),
+
+	// Logging
+	live(bind("LOG_LEVEL", "info", parseString, func(c *Config) *string { return &c.LogLevel })),
+
+	// Feature Flags
+	live(bind("FEATURE_FLAGS", FeatureDemoCredentials, parseList, func(c *Config) *[]string { return &c.FeatureFlags })),
+
+	// Admin
+	bind("ADMIN_EMAILS", "", parseList, func(c *Config) *[]string { return &c.AdminEmails }),
+
+	// Metrics
+	bind("METRICS_ENABLED", "true", parseBool, func(c *Config) *bool { return &c.MetricsEnabled }),
+	bind("METRICS_PATH", "/metrics", parseString, func(c *Config) *string { return &c.MetricsPath }),
+	secret(bind("METRICS_TOKEN", "", parseString, func(c *Config) *string { return &c.MetricsToken })),
+	bind("METRICS_PORT", "", parseString, func(c *Config) *string { return &c.MetricsPort }),
+
+	// Tracing
+	bind("TRACING_EXPORTER", "none", parseString, func(c *Config) *string { return &c.TracingExporter }),
+	bind("TRACING_FILE", "traces.json", parseString, func(c *Config) *string { return &c.TracingFile }),
+	bind("TRACING_OTLP_ENDPOINT", "", parseString, func(c *Config) *string { return &c.TracingOTLPEndpoint }),
+	bind("TRACING_SAMPLE_RATIO", "1", parseFloat, func(c *Config) *float64 { return &c.TracingSampleRatio }),
+
+	// API Configuration
+	bind("API_VERSION", "v1", parseString, func(c *Config) *string { return &c.APIVersion }),
+	bind("API_PREFIX", "/api", parseString, func(c *Config) *string { return &c.APIPrefix }),
+
+	// Demo User Credentials
+	bind("DEMO_USER_EMAIL", DefaultDemoUserEmail, parseString, func(c *Config) *string { return &c.DemoUserEmail }),
+	secret(bind("DEMO_USER_PASSWORD", DefaultDemoUserPassword, parseString, func(c *Config) *string { return &c.DemoUserPassword })),
+
+	// User Store (an empty path keeps registered users in memory only)
+	bind("USERS_FILE", "", parseString, func(c *Config) *string { return &c.UsersFile }),
+}
+
+// environmentDefaults override the built-in defaults for a given NODE_ENV; the config file,
+// environment and flags still take precedence
+var environmentDefaults = map[string]map[string]string{
+	"production": {
+		"SECURITY_HSTS_MAX_AGE": "365d",
+	},
+}
+
+// findSetting returns the setting for a key, accepting env (RATE_LIMIT_WINDOW_MS),
+// file (rate_limit_window_ms) and flag (rate-limit-window-ms) spellings
+func findSetting(key string) (setting, bool) {
+	normalized := strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+	for _, s := range settings {
+		if s.key == normalized {
+			return s, true
+		}
+	}
+	return setting{}, false
+}
+
+// flagName returns the command-line flag spelling of a key
+func flagName(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+}
+
+func parseString(s string) (string, error) {
+	return s, nil
+}
+
+// parseList splits a comma-separated list, dropping blanks; "none" is an explicit empty list
+func parseList(s string) ([]string, error) {
+	items := []string{}
+	if strings.TrimSpace(s) == "none" {
+		return items, nil
+	}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func parseInt(s string) (int, error) {
+	i, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("must be an integer")
+	}
+	return i, nil
+}
+
+// parseIntList splits a comma-separated list of integers, dropping blanks
+func parseIntList(s string) ([]int, error) {
+	items := []int{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		i, err := strconv.Atoi(item)
+		if err != nil {
+			return nil, fmt.Errorf("must be a comma-separated list of integers")
+		}
+		items = append(items, i)
+	}
+	return items, nil
+}
+
+func parseFloat(s string) (float64, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, fmt.Errorf("must be a number")
+	}
+	return f, nil
+}
+
+func parseBool(s string) (bool, error) {
+	b, err := strconv.ParseBool(strings.TrimSpace(s))
+	if err != nil {
+		return false, fmt.Errorf("must be true or false")
+	}
+	return b, nil
+}
+
+// parseDuration accepts Go durations ("90s", "24h") plus whole days ("7d")
+func parseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("must be a duration such as 30s, 15m, 24h or 7d")
+	}
+	return d, nil
+}
+
+// parseMilliseconds accepts a bare number of milliseconds or any duration parseDuration accepts
+func parseMilliseconds(s string) (time.Duration, error) {
+	if ms, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64); err == nil {
+		return time.Duration(ms) * time.Millisecond, nil
+	}
+	d, err := parseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("must be a number of milliseconds or a duration such as 15m")
+	}
+	return d, nil
+}
+
+// formatValue renders a parsed value the way it would be written in configuration
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case time.Duration:
+		return v.String()
+	case []string:
+		return strings.Join(v, ",")
+	case []int:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = strconv.Itoa(item)
+		}
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
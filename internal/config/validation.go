@@ -0,0 +1,262 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// insecureSecrets are well-known JWT secrets from the defaults and example files
+var insecureSecrets = map[string]bool{
+	DefaultJWTSecret:            true,
+	"super-secret-jwt-key-2025": true,
+}
+
+// referrerPolicies are the values Referrer-Policy accepts
+var referrerPolicies = map[string]bool{
+	"no-referrer":                     true,
+	"no-referrer-when-downgrade":      true,
+	"origin":                          true,
+	"origin-when-cross-origin":        true,
+	"same-origin":                     true,
+	"strict-origin":                   true,
+	"strict-origin-when-cross-origin": true,
+	"unsafe-url":                      true,
+}
+
+// corsMethods are the methods CORS_ALLOW_METHODS may list
+var corsMethods = map[string]bool{
+	"GET": true, "HEAD": true, "POST": true, "PUT": true, "DELETE": true, "PATCH": true, "OPTIONS": true,
+}
+
+// minHSTSPreloadMaxAge is the shortest max-age browsers accept for the HSTS preload list
+const minHSTSPreloadMaxAge = 365 * 24 * time.Hour
+
+// minProductionSecretLength is the shortest JWT secret accepted in production
+const minProductionSecretLength = 32
+
+// Problem describes one invalid configuration key
+type Problem struct {
+	Key     string
+	Message string
+}
+
+// Error lists every invalid configuration key
+type Error struct {
+	Problems []Problem
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString("invalid configuration:")
+	for _, problem := range e.Problems {
+		fmt.Fprintf(&b, "\n  - %s: %s", problem.Key, problem.Message)
+	}
+	return b.String()
+}
+
+// add records a problem, keeping only the first one reported for a key
+func (e *Error) add(key, message string) {
+	for _, problem := range e.Problems {
+		if problem.Key == key {
+			return
+		}
+	}
+	e.Problems = append(e.Problems, Problem{Key: key, Message: message})
+}
+
+// Validate reports every invalid configuration value
+func (c *Config) Validate() error {
+	problems := &Error{}
+
+	switch c.Environment {
+	case "development", "test", "staging", "production":
+	default:
+		problems.add("NODE_ENV", fmt.Sprintf("must be one of development, test, staging, production, got %q", c.Environment))
+	}
+	for key, port := range map[string]string{
+		"PORT":               c.Port,
+		"METRICS_PORT":       c.MetricsPort,
+		"HTTP_REDIRECT_PORT": c.HTTPRedirectPort,
+	} {
+		if port == "" && key != "PORT" {
+			continue
+		}
+		if n, err := strconv.Atoi(port); err != nil || n <= 0 || n > 65535 {
+			problems.add(key, fmt.Sprintf("must be a valid port number, got %q", port))
+		}
+	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		problems.add("TLS_CERT_FILE", "TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	for key, file := range map[string]string{
+		"TLS_CERT_FILE":      c.TLSCertFile,
+		"TLS_KEY_FILE":       c.TLSKeyFile,
+		"TLS_CLIENT_CA_FILE": c.TLSClientCAFile,
+	} {
+		if file == "" {
+			continue
+		}
+		if _, err := os.Stat(file); err != nil {
+			problems.add(key, err.Error())
+		}
+	}
+	if c.TLSClientCAFile != "" && !c.TLSEnabled() {
+		problems.add("TLS_CLIENT_CA_FILE", "requires TLS_CERT_FILE and TLS_KEY_FILE")
+	}
+	if c.HTTPRedirectPort != "" && !c.TLSEnabled() {
+		problems.add("HTTP_REDIRECT_PORT", "requires TLS_CERT_FILE and TLS_KEY_FILE")
+	}
+	if c.TLSEnabled() && c.TLSReloadInterval <= 0 {
+		problems.add("TLS_RELOAD_INTERVAL", "must be a positive duration")
+	}
+
+	if c.ShutdownDrainPeriod < 0 {
+		problems.add("SHUTDOWN_DRAIN_PERIOD", "must not be negative")
+	}
+	if c.ShutdownTimeout <= 0 {
+		problems.add("SHUTDOWN_TIMEOUT", "must be a positive duration")
+	}
+
+	if c.JWTSecret == "" {
+		problems.add("JWT_SECRET", "must not be empty")
+	}
+	if c.JWTExpiresIn <= 0 {
+		problems.add("JWT_EXPIRES_IN", "must be a positive duration")
+	}
+	if c.JWTRefreshExpiresIn <= 0 {
+		problems.add("JWT_REFRESH_EXPIRES_IN", "must be a positive duration")
+	}
+	if c.RateLimitWindowMS <= 0 {
+		problems.add("RATE_LIMIT_WINDOW_MS", "must be a positive duration")
+	}
+	if c.RateLimitMaxRequests <= 0 {
+		problems.add("RATE_LIMIT_MAX_REQUESTS", "must be a positive integer")
+	}
+
+	if c.CacheMaxEntries < 0 {
+		problems.add("CACHE_MAX_ENTRIES", "must not be negative")
+	}
+	if c.CacheTTL < 0 {
+		problems.add("CACHE_TTL", "must not be negative")
+	}
+
+	for i, bound := range c.FacetPriceBuckets {
+		if bound <= 0 || (i > 0 && bound <= c.FacetPriceBuckets[i-1]) {
+			problems.add("FACET_PRICE_BUCKETS", "must be positive and strictly increasing")
+		}
+	}
+
+	switch c.LogLevel {
+	case "debug", "info", "warn", "error":
+	default:
+		problems.add("LOG_LEVEL", fmt.Sprintf("must be one of debug, info, warn, error, got %q", c.LogLevel))
+	}
+
+	c.validateCORS(problems)
+	c.validateSecurityHeaders(problems)
+
+	for _, flag := range c.FeatureFlags {
+		if !knownFeatures[flag] {
+			problems.add("FEATURE_FLAGS", fmt.Sprintf("unknown feature flag %q", flag))
+		}
+	}
+	for _, email := range c.AdminEmails {
+		if !strings.Contains(email, "@") {
+			problems.add("ADMIN_EMAILS", fmt.Sprintf("must be a comma-separated list of emails, got %q", email))
+		}
+	}
+
+	if !strings.HasPrefix(c.MetricsPath, "/") {
+		problems.add("METRICS_PATH", "must start with /")
+	}
+	switch c.TracingExporter {
+	case "none", "stdout", "file", "otlp":
+	default:
+		problems.add("TRACING_EXPORTER", fmt.Sprintf("must be one of none, stdout, file, otlp, got %q", c.TracingExporter))
+	}
+	if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+		problems.add("TRACING_SAMPLE_RATIO", "must be between 0 and 1")
+	}
+
+	if c.IsProduction() {
+		if insecureSecrets[c.JWTSecret] {
+			problems.add("JWT_SECRET", "must be changed from the default in production")
+		} else if len(c.JWTSecret) < minProductionSecretLength {
+			problems.add("JWT_SECRET", fmt.Sprintf("must be at least %d characters in production", minProductionSecretLength))
+		}
+		if c.DemoUserEmail == DefaultDemoUserEmail {
+			problems.add("DEMO_USER_EMAIL", "must not use the demo default in production")
+		}
+		if c.DemoUserPassword == DefaultDemoUserPassword {
+			problems.add("DEMO_USER_PASSWORD", "must not use the demo default in production")
+		}
+	}
+
+	if len(problems.Problems) > 0 {
+		return problems
+	}
+	return nil
+}
+
+func (c *Config) validateCORS(problems *Error) {
+	if len(c.CORSAllowOrigins) == 0 {
+		problems.add("CORS_ALLOW_ORIGINS", "must list at least one origin, or *")
+	}
+	for _, origin := range c.CORSAllowOrigins {
+		if origin == "*" {
+			if len(c.CORSAllowOrigins) > 1 {
+				problems.add("CORS_ALLOW_ORIGINS", "* cannot be combined with other origins")
+			}
+			if c.CORSAllowCredentials {
+				problems.add("CORS_ALLOW_ORIGINS", "must list explicit origins when CORS_ALLOW_CREDENTIALS is true")
+			}
+			continue
+		}
+		if !validOrigin(origin) {
+			problems.add("CORS_ALLOW_ORIGINS", fmt.Sprintf("invalid origin %q, expected scheme://host[:port] or scheme://*.domain", origin))
+		}
+	}
+	for _, method := range c.CORSAllowMethods {
+		if !corsMethods[method] {
+			problems.add("CORS_ALLOW_METHODS", fmt.Sprintf("unsupported method %q", method))
+		}
+	}
+	if c.CORSMaxAge < 0 {
+		problems.add("CORS_MAX_AGE", "must not be negative")
+	}
+}
+
+func (c *Config) validateSecurityHeaders(problems *Error) {
+	if !referrerPolicies[c.SecurityReferrerPolicy] {
+		problems.add("SECURITY_REFERRER_POLICY", fmt.Sprintf("unknown referrer policy %q", c.SecurityReferrerPolicy))
+	}
+	if c.SecurityCSPReportOnly && c.SecurityCSP == "" {
+		problems.add("SECURITY_CSP_REPORT_ONLY", "requires SECURITY_CSP")
+	}
+	if c.SecurityHSTSMaxAge < 0 {
+		problems.add("SECURITY_HSTS_MAX_AGE", "must not be negative")
+	}
+	if c.SecurityHSTSPreload && (!c.SecurityHSTSIncludeSubdomains || c.SecurityHSTSMaxAge < minHSTSPreloadMaxAge) {
+		problems.add("SECURITY_HSTS_PRELOAD", "requires SECURITY_HSTS_INCLUDE_SUBDOMAINS and a SECURITY_HSTS_MAX_AGE of at least 365d")
+	}
+}
+
+// validOrigin reports whether origin is scheme://host[:port], optionally with a leading *. subdomain wildcard
+func validOrigin(origin string) bool {
+	origin = strings.Replace(origin, "://*.", "://", 1)
+	u, err := url.Parse(origin)
+	if err != nil || u.Scheme == "" || u.Host == "" || strings.Contains(u.Host, "*") {
+		return false
+	}
+	return (u.Path == "" || u.Path == "/") && u.RawQuery == "" && u.Fragment == ""
+}
+
+// TLSEnabled reports whether the server should terminate TLS itself
+func (c *Config) TLSEnabled() bool {
+	return c.TLSCertFile != "" && c.TLSKeyFile != ""
+}
@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"errors"
+
+	"housing-api/internal/config"
+	"housing-api/internal/models"
+	"housing-api/internal/services"
+	"housing-api/pkg/logger"
+	"housing-api/pkg/response"
+	"housing-api/pkg/search"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AdminController handles operational endpoints for admin users
+type AdminController struct {
+	listingService *services.ListingService
+}
+
+func NewAdminController(listingService *services.ListingService) *AdminController {
+	return &AdminController{
+		listingService: listingService,
+	}
+}
+
+// ReloadConfig godoc
+// @Summary Reload configuration
+// @Description Re-read configuration and apply settings that can change live (log level, rate limits, CORS origins, feature flags). Other changed keys are reported as requiring a restart.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=config.ReloadResult}
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 422 {object} models.APIResponse
+// @Router /admin/config/reload [post]
+func (c *AdminController) ReloadConfig(ctx *fiber.Ctx) error {
+	result, err := config.Reload()
+	if err != nil {
+		logger.WarnContext(ctx.UserContext(), "Configuration reload rejected", "error", err.Error())
+		return response.UnprocessableEntity(ctx, "Configuration reload failed", err)
+	}
+
+	logger.InfoContext(ctx.UserContext(), "Configuration reloaded",
+		"applied", result.Applied,
+		"requires_restart", result.RequiresRestart,
+		"by", ctx.Locals("userEmail"),
+	)
+	return response.Success(ctx, "Configuration reloaded", result)
+}
+
+// ReloadListings godoc
+// @Summary Reload listing data
+// @Description Re-read listings from disk and invalidate cached results
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /admin/listings/reload [post]
+func (c *AdminController) ReloadListings(ctx *fiber.Ctx) error {
+	if err := c.listingService.ReloadListings(ctx.UserContext()); err != nil {
+		return response.InternalServerError(ctx, "Failed to reload listings", err)
+	}
+
+	logger.InfoContext(ctx.UserContext(), "Listings reloaded", "by", ctx.Locals("userEmail"))
+	return response.Success(ctx, "Listings reloaded", fiber.Map{
+		"last_modified": c.listingService.LastModified(),
+	})
+}
+
+// GetCacheStats godoc
+// @Summary Cache statistics
+// @Description Hit and miss counts and sizes of the in-process result caches
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=[]cache.Stats}
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Router /admin/cache [get]
+func (c *AdminController) GetCacheStats(ctx *fiber.Ctx) error {
+	return response.Success(ctx, "Cache statistics retrieved successfully", c.listingService.CacheStats())
+}
+
+// GetSynonyms godoc
+// @Summary Search synonyms
+// @Description The synonym groups and abbreviations applied to search queries and the location, city and property type filters
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=search.Dictionary}
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Router /admin/synonyms [get]
+func (c *AdminController) GetSynonyms(ctx *fiber.Ctx) error {
+	return response.Success(ctx, "Synonyms retrieved successfully", c.listingService.GetSynonyms())
+}
+
+// UpdateSynonyms godoc
+// @Summary Replace search synonyms
+// @Description Replace the synonym dictionary. It is saved to the synonyms file and applied immediately.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param dictionary body search.Dictionary true "Synonym groups and abbreviations"
+// @Success 200 {object} models.APIResponse{data=search.Dictionary}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 422 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /admin/synonyms [put]
+func (c *AdminController) UpdateSynonyms(ctx *fiber.Ctx) error {
+	var dictionary search.Dictionary
+	if err := ctx.BodyParser(&dictionary); err != nil {
+		return response.BadRequest(ctx, "Invalid request body", err)
+	}
+
+	if err := c.listingService.UpdateSynonyms(ctx.UserContext(), dictionary); err != nil {
+		var validationErr models.ValidationError
+		if errors.As(err, &validationErr) {
+			return response.UnprocessableEntity(ctx, "Invalid synonyms", err)
+		}
+		return response.InternalServerError(ctx, "Failed to save synonyms", err)
+	}
+
+	logger.InfoContext(ctx.UserContext(), "Synonyms updated", "by", ctx.Locals("userEmail"))
+	return response.Success(ctx, "Synonyms updated", c.listingService.GetSynonyms())
+}
+
+// ReloadSynonyms godoc
+// @Summary Reload search synonyms
+// @Description Re-read the synonyms file after editing it by hand. An invalid file leaves the current synonyms in place.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=search.Dictionary}
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 422 {object} models.APIResponse
+// @Router /admin/synonyms/reload [post]
+func (c *AdminController) ReloadSynonyms(ctx *fiber.Ctx) error {
+	if err := c.listingService.ReloadSynonyms(ctx.UserContext()); err != nil {
+		logger.WarnContext(ctx.UserContext(), "Synonyms reload rejected", "error", err.Error())
+		return response.UnprocessableEntity(ctx, "Synonyms reload failed", err)
+	}
+
+	logger.InfoContext(ctx.UserContext(), "Synonyms reloaded", "by", ctx.Locals("userEmail"))
+	return response.Success(ctx, "Synonyms reloaded", c.listingService.GetSynonyms())
+}
+
+// GetGeocodingReport godoc
+// @Summary Geocoding report
+// @Description How the loaded listings got their coordinates: carried in the source data, or geocoded from the gazetteer by precision. Listings whose location the gazetteer doesn't know are listed as unresolved.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=models.GeocodingReport}
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Router /admin/geocoding [get]
+func (c *AdminController) GetGeocodingReport(ctx *fiber.Ctx) error {
+	return response.Success(ctx, "Geocoding report retrieved successfully", c.listingService.GetGeocodingReport())
+}
+
+// ReloadGazetteer godoc
+// @Summary Reload the gazetteer
+// @Description Re-read the gazetteer file after editing it by hand and geocode the listings again. An invalid file leaves the current gazetteer in place.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=models.GeocodingReport}
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 422 {object} models.APIResponse
+// @Router /admin/gazetteer/reload [post]
+func (c *AdminController) ReloadGazetteer(ctx *fiber.Ctx) error {
+	if err := c.listingService.ReloadGazetteer(ctx.UserContext()); err != nil {
+		logger.WarnContext(ctx.UserContext(), "Gazetteer reload rejected", "error", err.Error())
+		return response.UnprocessableEntity(ctx, "Gazetteer reload failed", err)
+	}
+
+	logger.InfoContext(ctx.UserContext(), "Gazetteer reloaded", "by", ctx.Locals("userEmail"))
+	return response.Success(ctx, "Gazetteer reloaded", c.listingService.GetGeocodingReport())
+}
@@ -1,10 +1,10 @@
 package controllers
 
 import (
-	"housing-api/internal/config"
 	"housing-api/internal/models"
 	"housing-api/internal/services"
 	"housing-api/internal/utils"
+	"housing-api/pkg/metrics"
 	"housing-api/pkg/response"
 
 	"github.com/gofiber/fiber/v2"
@@ -15,9 +15,9 @@ type AuthController struct {
 	authService *services.AuthService
 }
 
-func NewAuthController(cfg *config.Config) *AuthController {
+func NewAuthController(authService *services.AuthService) *AuthController {
 	return &AuthController{
-		authService: services.NewAuthService(cfg),
+		authService: authService,
 	}
 }
 
@@ -47,8 +47,9 @@ func (c *AuthController) Login(ctx *fiber.Ctx) error {
 	}
 
 	// Authenticate user
-	authResponse, err := c.authService.Login(req)
+	authResponse, err := c.authService.Login(ctx.UserContext(), req)
 	if err != nil {
+		metrics.IncAuthFailure("invalid_credentials")
 		return response.Unauthorized(ctx, "Authentication failed", err)
 	}
 
@@ -81,7 +82,7 @@ func (c *AuthController) Register(ctx *fiber.Ctx) error {
 	}
 
 	// Register user
-	authResponse, err := c.authService.Register(req)
+	authResponse, err := c.authService.Register(ctx.UserContext(), req)
 	if err != nil {
 		if err.Error() == "user with email "+req.Email+" already exists" {
 			return response.Conflict(ctx, "User already exists", err)
@@ -120,6 +121,7 @@ func (c *AuthController) RefreshToken(ctx *fiber.Ctx) error {
 	// Refresh token
 	authResponse, err := c.authService.RefreshToken(refreshToken)
 	if err != nil {
+		metrics.IncAuthFailure("invalid_refresh_token")
 		return response.Unauthorized(ctx, "Invalid refresh token", err)
 	}
 
@@ -167,4 +169,4 @@ func (c *AuthController) Logout(ctx *fiber.Ctx) error {
 	return response.Success(ctx, "Logout successful", map[string]string{
 		"message": "Please discard your tokens on the client side",
 	})
-}
\ No newline at end of file
+}
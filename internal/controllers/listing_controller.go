@@ -1,30 +1,35 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
+	"strings"
 
 	"housing-api/internal/models"
 	"housing-api/internal/services"
+	"housing-api/pkg/pagination"
 	"housing-api/pkg/response"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// Suggestion limits for GetSuggestions
+const (
+	defaultSuggestionLimit = 10
+	maxSuggestionLimit     = 25
+)
+
 // ListingController handles listing-related HTTP requests
 type ListingController struct {
 	listingService *services.ListingService
 }
 
 // NewListingController creates a new listing controller
-func NewListingController() (*ListingController, error) {
-	listingService, err := services.NewListingService()
-	if err != nil {
-		return nil, err
-	}
-
+func NewListingController(listingService *services.ListingService) *ListingController {
 	return &ListingController{
 		listingService: listingService,
-	}, nil
+	}
 }
 
 // GetListings godoc
@@ -35,17 +40,32 @@ func NewListingController() (*ListingController, error) {
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
-// @Param location query string false "Filter by location"
-// @Param property_type query string false "Filter by property type"
-// @Param city query string false "Filter by city"
+// @Param cursor query string false "Cursor from a previous next_cursor (overrides page)"
+// @Param sort query string false "Sort fields, - prefix for descending (e.g. -price,bedrooms; distance with near)"
+// @Param facets query string false "Facets to count (property_type, listing_type, city, area, bedrooms, price) or all"
+// @Param location query []string false "Filter by location, e.g. Lekki, Lagos; repeated values match any" collectionFormat(multi)
+// @Param property_type query []string false "Filter by property type; comma-separated or repeated values match any" collectionFormat(multi)
+// @Param city query []string false "Filter by city; comma-separated or repeated values match any" collectionFormat(multi)
+// @Param exclude_location query []string false "Exclude locations, areas or cities (exact match)" collectionFormat(multi)
+// @Param exclude_property_type query []string false "Exclude property types" collectionFormat(multi)
+// @Param exclude_city query []string false "Exclude cities (exact match)" collectionFormat(multi)
+// @Param filter query string false "Filter expression, e.g. (city:Lagos OR city:Abuja) AND price<=3000000"
 // @Param min_price query int false "Minimum price"
 // @Param max_price query int false "Maximum price"
 // @Param min_bedrooms query int false "Minimum bedrooms"
 // @Param max_bedrooms query int false "Maximum bedrooms"
 // @Param min_bathrooms query int false "Minimum bathrooms"
 // @Param max_bathrooms query int false "Maximum bathrooms"
+// @Param near query string false "Point as lat,lng; adds distance_km and allows sort=distance"
+// @Param radius_km query number false "Only listings within this many km of near"
+// @Param min_lat query number false "Bounding box southern edge"
+// @Param max_lat query number false "Bounding box northern edge"
+// @Param min_lng query number false "Bounding box western edge"
+// @Param max_lng query number false "Bounding box eastern edge"
+// @Param format query string false "Response format (json, csv, xml, msgpack); overrides Accept"
 // @Success 200 {object} models.APIResponse{data=models.PaginatedResponse}
 // @Failure 400 {object} models.APIResponse
+// @Failure 422 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
 // @Router /listings [get]
 func (c *ListingController) GetListings(ctx *fiber.Ctx) error {
@@ -62,12 +82,19 @@ func (c *ListingController) GetListings(ctx *fiber.Ctx) error {
 	}
 
 	// Get listings
-	result, err := c.listingService.GetListings(filter, paginationQuery)
+	result, err := c.listingService.GetListings(ctx.UserContext(), filter, paginationQuery)
+	var validationErr models.ValidationError
+	if errors.As(err, &validationErr) {
+		return response.ValidationError(ctx, "Invalid query parameters", []models.ValidationError{validationErr})
+	}
+	if errors.Is(err, pagination.ErrInvalidCursor) {
+		return response.BadRequest(ctx, "Invalid pagination cursor", err)
+	}
 	if err != nil {
 		return response.InternalServerError(ctx, "Failed to get listings", err)
 	}
 
-	return response.Success(ctx, "Listings retrieved successfully", result)
+	return response.Success(ctx, "Listings retrieved successfully", withPaginationLinks(ctx, result))
 }
 
 // GetListingByID godoc
@@ -101,18 +128,32 @@ func (c *ListingController) GetListingByID(ctx *fiber.Ctx) error {
 
 // SearchListings godoc
 // @Summary Search listings
-// @Description Search housing listings with query string
+// @Description Full-text search over title, location and property type, ranked by relevance and combinable with filters
 // @Tags listings
 // @Accept json
 // @Produce json
 // @Param q query string true "Search query"
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
-// @Param location query string false "Filter by location"
-// @Param property_type query string false "Filter by property type"
-// @Param city query string false "Filter by city"
+// @Param cursor query string false "Cursor from a previous next_cursor (overrides page)"
+// @Param sort query string false "Sort fields, - prefix for descending (e.g. -price,bedrooms; distance with near)"
+// @Param facets query string false "Facets to count (property_type, listing_type, city, area, bedrooms, price) or all"
+// @Param location query []string false "Filter by location, e.g. Lekki, Lagos; repeated values match any" collectionFormat(multi)
+// @Param property_type query []string false "Filter by property type; comma-separated or repeated values match any" collectionFormat(multi)
+// @Param city query []string false "Filter by city; comma-separated or repeated values match any" collectionFormat(multi)
+// @Param exclude_location query []string false "Exclude locations, areas or cities (exact match)" collectionFormat(multi)
+// @Param exclude_property_type query []string false "Exclude property types" collectionFormat(multi)
+// @Param exclude_city query []string false "Exclude cities (exact match)" collectionFormat(multi)
+// @Param filter query string false "Filter expression, e.g. (city:Lagos OR city:Abuja) AND price<=3000000"
+// @Param near query string false "Point as lat,lng; adds distance_km and allows sort=distance"
+// @Param radius_km query number false "Only listings within this many km of near"
+// @Param min_lat query number false "Bounding box southern edge"
+// @Param max_lat query number false "Bounding box northern edge"
+// @Param min_lng query number false "Bounding box western edge"
+// @Param max_lng query number false "Bounding box eastern edge"
 // @Success 200 {object} models.APIResponse{data=models.PaginatedResponse}
 // @Failure 400 {object} models.APIResponse
+// @Failure 422 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
 // @Router /listings/search [get]
 func (c *ListingController) SearchListings(ctx *fiber.Ctx) error {
@@ -135,12 +176,44 @@ func (c *ListingController) SearchListings(ctx *fiber.Ctx) error {
 	}
 
 	// Search listings
-	result, err := c.listingService.SearchListings(query, filter, paginationQuery)
+	result, err := c.listingService.SearchListings(ctx.UserContext(), query, filter, paginationQuery)
+	var validationErr models.ValidationError
+	if errors.As(err, &validationErr) {
+		return response.ValidationError(ctx, "Invalid query parameters", []models.ValidationError{validationErr})
+	}
+	if errors.Is(err, pagination.ErrInvalidCursor) {
+		return response.BadRequest(ctx, "Invalid pagination cursor", err)
+	}
 	if err != nil {
 		return response.InternalServerError(ctx, "Failed to search listings", err)
 	}
 
-	return response.Success(ctx, "Search completed successfully", result)
+	return response.Success(ctx, "Search completed successfully", withPaginationLinks(ctx, result))
+}
+
+// GetSuggestions godoc
+// @Summary Autocomplete suggestions
+// @Description Ranked completions for areas, cities, property types and listing titles matching a prefix, with listing counts
+// @Tags listings
+// @Accept json
+// @Produce json
+// @Param q query string true "Partial query"
+// @Param limit query int false "Maximum suggestions" default(10)
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Router /listings/suggest [get]
+func (c *ListingController) GetSuggestions(ctx *fiber.Ctx) error {
+	query := ctx.Query("q")
+	if strings.TrimSpace(query) == "" {
+		return response.BadRequest(ctx, "Suggestion query is required", nil)
+	}
+
+	limit := ctx.QueryInt("limit", defaultSuggestionLimit)
+	if limit < 1 || limit > maxSuggestionLimit {
+		return response.BadRequest(ctx, fmt.Sprintf("limit must be between 1 and %d", maxSuggestionLimit), nil)
+	}
+
+	return response.Success(ctx, "Suggestions retrieved successfully", c.listingService.GetSuggestions(query, limit))
 }
 
 // GetFiltersMetadata godoc
@@ -179,4 +252,4 @@ func (c *ListingController) GetListingStats(ctx *fiber.Ctx) error {
 	}
 
 	return response.Success(ctx, "Listing statistics retrieved successfully", stats)
-}
\ No newline at end of file
+}
@@ -0,0 +1,269 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"housing-api/internal/models"
+	"housing-api/internal/services"
+	"housing-api/pkg/odata"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxODataPageSize caps the results of one GetProperties response; larger $top values are
+// served in pages linked by @odata.nextLink
+const maxODataPageSize = 100
+
+// odataQueryOptions are the system query options GetProperties understands; any other $ option
+// is rejected rather than silently ignored
+var odataQueryOptions = map[string]bool{
+	"$filter": true, "$select": true, "$orderby": true, "$top": true, "$skip": true, "$count": true,
+}
+
+// ODataController serves listings as the RESO Property resource over OData v4
+type ODataController struct {
+	listingService *services.ListingService
+
+	// root is the path of the OData service root, e.g. /api/v1/odata
+	root string
+}
+
+// NewODataController creates an OData controller for the service rooted at root
+func NewODataController(listingService *services.ListingService, root string) *ODataController {
+	return &ODataController{
+		listingService: listingService,
+		root:           root,
+	}
+}
+
+// GetServiceDocument godoc
+// @Summary OData service document
+// @Description Lists the entity sets of the RESO OData service
+// @Tags odata
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /odata [get]
+func (c *ODataController) GetServiceDocument(ctx *fiber.Ctx) error {
+	return c.write(ctx, fiber.StatusOK, fiber.Map{
+		"@odata.context": c.url(ctx, "/$metadata"),
+		"value": []fiber.Map{
+			{"name": "Property", "kind": "EntitySet", "url": "Property"},
+		},
+	})
+}
+
+// GetMetadata godoc
+// @Summary OData metadata
+// @Description CSDL document describing the RESO Property resource and its fields
+// @Tags odata
+// @Produce xml
+// @Success 200 {string} string
+// @Failure 500 {object} odata.ErrorResponse
+// @Router /odata/$metadata [get]
+func (c *ODataController) GetMetadata(ctx *fiber.Ctx) error {
+	document, err := odata.Metadata(models.ResoNamespace, models.ResoEntityType())
+	if err != nil {
+		return c.error(ctx, fiber.StatusInternalServerError, "InternalServerError", "Failed to build metadata", "")
+	}
+
+	ctx.Set("OData-Version", "4.0")
+	ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationXMLCharsetUTF8)
+	return ctx.Send(document)
+}
+
+// GetProperties godoc
+// @Summary Query RESO properties
+// @Description Listings as RESO Property entities, with OData $filter, $select, $orderby, $top, $skip and $count
+// @Tags odata
+// @Produce json
+// @Param $filter query string false "OData filter, e.g. City eq 'Lagos' and ListPrice le 3000000"
+// @Param $select query string false "Comma-separated RESO fields to return"
+// @Param $orderby query string false "RESO fields with optional asc/desc, e.g. ListPrice desc"
+// @Param $top query int false "Maximum number of results; pages of at most 100 are linked by @odata.nextLink"
+// @Param $skip query int false "Number of results to skip"
+// @Param $count query bool false "Include @odata.count, the total number of matches"
+// @Success 200 {object} odata.Collection
+// @Failure 400 {object} odata.ErrorResponse
+// @Failure 500 {object} odata.ErrorResponse
+// @Router /odata/Property [get]
+func (c *ODataController) GetProperties(ctx *fiber.Ctx) error {
+	var unsupported string
+	ctx.Request().URI().QueryArgs().VisitAll(func(key, _ []byte) {
+		if name := string(key); strings.HasPrefix(name, "$") && !odataQueryOptions[name] && unsupported == "" {
+			unsupported = name
+		}
+	})
+	if unsupported != "" {
+		return c.error(ctx, fiber.StatusBadRequest, "BadRequest", fmt.Sprintf("query option %s is not supported", unsupported), unsupported)
+	}
+
+	var filter models.ListingFilter
+	if expression := ctx.Query("$filter"); strings.TrimSpace(expression) != "" {
+		node, err := odata.ParseFilter(expression, models.ResoFilterFields())
+		if err != nil {
+			return c.error(ctx, fiber.StatusBadRequest, "BadRequest", err.Error(), "$filter")
+		}
+		filter.Expression = node
+	}
+
+	selected := odata.ParseSelect(ctx.Query("$select"))
+	for _, name := range selected {
+		if _, ok := models.FindResoField(name); !ok {
+			return c.error(ctx, fiber.StatusBadRequest, "BadRequest", "unknown property "+name, "$select")
+		}
+	}
+
+	sorting := models.Sort{{Field: models.DefaultSort}}
+	if spec := ctx.Query("$orderby"); spec != "" {
+		orderBy, err := odata.ParseOrderBy(spec)
+		if err != nil {
+			return c.error(ctx, fiber.StatusBadRequest, "BadRequest", err.Error(), "$orderby")
+		}
+		if sorting, err = models.ResoSort(orderBy); err != nil {
+			return c.error(ctx, fiber.StatusBadRequest, "BadRequest", validationMessage(err), "$orderby")
+		}
+	}
+
+	top, err := queryCount(ctx, "$top", -1)
+	if err != nil {
+		return c.error(ctx, fiber.StatusBadRequest, "BadRequest", err.Error(), "$top")
+	}
+	skip, err := queryCount(ctx, "$skip", 0)
+	if err != nil {
+		return c.error(ctx, fiber.StatusBadRequest, "BadRequest", err.Error(), "$skip")
+	}
+	count, err := strconv.ParseBool(ctx.Query("$count", "false"))
+	if err != nil {
+		return c.error(ctx, fiber.StatusBadRequest, "BadRequest", "$count must be true or false", "$count")
+	}
+
+	pageSize := maxODataPageSize
+	if top >= 0 && top < pageSize {
+		pageSize = top
+	}
+
+	page, err := c.listingService.QueryListings(ctx.UserContext(), filter, sorting, skip, pageSize)
+	var validationErr models.ValidationError
+	if errors.As(err, &validationErr) {
+		return c.error(ctx, fiber.StatusBadRequest, "BadRequest", validationErr.Message, "$filter")
+	}
+	if err != nil {
+		return c.error(ctx, fiber.StatusInternalServerError, "InternalServerError", "Failed to get properties", "")
+	}
+
+	collection := odata.Collection{
+		Context: c.url(ctx, "/$metadata#Property"),
+		Value:   make([]map[string]any, len(page.Items)),
+	}
+	if len(selected) > 0 {
+		collection.Context = c.url(ctx, "/$metadata#Property("+strings.Join(selected, ",")+")")
+	}
+	for i := range page.Items {
+		collection.Value[i] = page.Items[i].ResoProperty(selected)
+	}
+	if count {
+		collection.Count = &page.Total
+	}
+
+	// Link the next page while matches remain and $top (when given) hasn't been reached
+	returned := len(page.Items)
+	if int64(skip+returned) < page.Total && returned > 0 && (top < 0 || top > returned) {
+		query := url.Values{}
+		ctx.Request().URI().QueryArgs().VisitAll(func(key, value []byte) {
+			query.Add(string(key), string(value))
+		})
+		query.Set("$skip", strconv.Itoa(skip+returned))
+		if top >= 0 {
+			query.Set("$top", strconv.Itoa(top-returned))
+		}
+		collection.NextLink = c.url(ctx, "/Property") + "?" + query.Encode()
+	}
+
+	return c.write(ctx, fiber.StatusOK, collection)
+}
+
+// GetProperty godoc
+// @Summary Get a RESO property
+// @Description A single listing as a RESO Property, addressed by its quoted ListingKey, e.g. Property('12')
+// @Tags odata
+// @Produce json
+// @Param key path string true "Quoted ListingKey, e.g. '12'"
+// @Param $select query string false "Comma-separated RESO fields to return"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} odata.ErrorResponse
+// @Failure 404 {object} odata.ErrorResponse
+// @Router /odata/Property({key}) [get]
+func (c *ODataController) GetProperty(ctx *fiber.Ctx) error {
+	key, err := url.PathUnescape(ctx.Params("key"))
+	if err != nil || len(key) < 2 || !strings.HasPrefix(key, "'") || !strings.HasSuffix(key, "'") {
+		return c.error(ctx, fiber.StatusBadRequest, "BadRequest", "ListingKey must be a quoted string such as '12'", "ListingKey")
+	}
+	id, err := strconv.Atoi(key[1 : len(key)-1])
+	if err != nil {
+		return c.error(ctx, fiber.StatusNotFound, "NotFound", "no property with ListingKey "+key, "")
+	}
+
+	selected := odata.ParseSelect(ctx.Query("$select"))
+	for _, name := range selected {
+		if _, ok := models.FindResoField(name); !ok {
+			return c.error(ctx, fiber.StatusBadRequest, "BadRequest", "unknown property "+name, "$select")
+		}
+	}
+
+	listing, err := c.listingService.GetListingByID(id)
+	if err != nil {
+		return c.error(ctx, fiber.StatusNotFound, "NotFound", "no property with ListingKey "+key, "")
+	}
+
+	entity := listing.ResoProperty(selected)
+	entity["@odata.context"] = c.url(ctx, "/$metadata#Property/$entity")
+	return c.write(ctx, fiber.StatusOK, entity)
+}
+
+// url returns the absolute URL of path under the service root
+func (c *ODataController) url(ctx *fiber.Ctx, path string) string {
+	return ctx.BaseURL() + c.root + path
+}
+
+// write sends body as OData JSON with minimal metadata
+func (c *ODataController) write(ctx *fiber.Ctx, status int, body any) error {
+	ctx.Set("OData-Version", "4.0")
+	if err := ctx.Status(status).JSON(body); err != nil {
+		return err
+	}
+	ctx.Set(fiber.HeaderContentType, "application/json;odata.metadata=minimal")
+	return nil
+}
+
+// error sends an OData error response; target names the query option at fault, if any
+func (c *ODataController) error(ctx *fiber.Ctx, status int, code, message, target string) error {
+	return c.write(ctx, status, odata.ErrorResponse{
+		Error: odata.ErrorDetail{Code: code, Message: message, Target: target},
+	})
+}
+
+// queryCount parses a non-negative integer query option, returning fallback when it is absent
+func queryCount(ctx *fiber.Ctx, name string, fallback int) (int, error) {
+	value := ctx.Query(name)
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", name)
+	}
+	return n, nil
+}
+
+// validationMessage returns the message of a ValidationError, or the error text otherwise
+func validationMessage(err error) string {
+	var validationErr models.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Message
+	}
+	return err.Error()
+}
@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	"net/url"
+
+	"housing-api/internal/models"
+	"housing-api/pkg/pagination"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// withPaginationLinks returns a copy of result whose metadata carries page links built from the
+// request path, and sets the matching Link header. The result itself may be cached and is not
+// modified. Links are relative, as responses are cached across hosts and the Host header is
+// client-supplied.
+func withPaginationLinks(ctx *fiber.Ctx, result *models.PaginatedResponse) *models.PaginatedResponse {
+	query := url.Values{}
+	ctx.Request().URI().QueryArgs().VisitAll(func(key, value []byte) {
+		query.Add(string(key), string(value))
+	})
+
+	linked := *result
+	linked.Meta.Links = pagination.BuildLinks(ctx.Path(), query, result.Meta)
+	ctx.Set(fiber.HeaderLink, pagination.LinkHeader(linked.Meta.Links))
+
+	return &linked
+}
@@ -0,0 +1,173 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"housing-api/pkg/response"
+	"housing-api/pkg/version"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Check statuses
+const (
+	StatusPass = "pass"
+	StatusFail = "fail"
+)
+
+// checkTimeout bounds how long a single readiness check may take
+const checkTimeout = 2 * time.Second
+
+// CheckFunc reports whether a dependency is ready; a nil error means ready
+type CheckFunc func(ctx context.Context) error
+
+// CheckResult is the outcome of a single readiness check
+type CheckResult struct {
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`
+	Error      string  `json:"error,omitempty"`
+	DurationMS float64 `json:"duration_ms"`
+}
+
+// Report is the body returned by the probe endpoints
+type Report struct {
+	Status        string        `json:"status"`
+	Checks        []CheckResult `json:"checks,omitempty"`
+	UptimeSeconds float64       `json:"uptime_seconds"`
+	Build         version.Info  `json:"build"`
+}
+
+var (
+	mu        sync.RWMutex
+	checks    = map[string]CheckFunc{}
+	startedAt = time.Now()
+	draining  atomic.Bool
+)
+
+// SetDraining marks the service as shutting down so readiness fails and load balancers stop routing to it
+func SetDraining(value bool) {
+	draining.Store(value)
+}
+
+// Register adds a named readiness check, replacing any check with the same name
+func Register(name string, check CheckFunc) {
+	mu.Lock()
+	defer mu.Unlock()
+	checks[name] = check
+}
+
+// Unregister removes a named readiness check
+func Unregister(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(checks, name)
+}
+
+// Liveness reports that the process is up; it deliberately checks no dependencies
+func Liveness() Report {
+	return Report{
+		Status:        StatusPass,
+		UptimeSeconds: time.Since(startedAt).Seconds(),
+		Build:         version.Get(),
+	}
+}
+
+// Readiness runs every registered check concurrently and reports per-check results and timings
+func Readiness(ctx context.Context) Report {
+	mu.RLock()
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	funcs := make([]CheckFunc, len(names))
+	for i, name := range names {
+		funcs[i] = checks[name]
+	}
+	mu.RUnlock()
+
+	results := make([]CheckResult, len(names))
+	var wg sync.WaitGroup
+	for i := range names {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = runCheck(ctx, names[i], funcs[i])
+		}(i)
+	}
+	wg.Wait()
+
+	if draining.Load() {
+		results = append(results, CheckResult{Name: "shutdown", Status: StatusFail, Error: "server is draining"})
+	}
+
+	report := Liveness()
+	report.Checks = results
+	for _, result := range results {
+		if result.Status != StatusPass {
+			report.Status = StatusFail
+			break
+		}
+	}
+
+	return report
+}
+
+// runCheck executes a single check with a timeout, reporting a panic as a failure
+func runCheck(ctx context.Context, name string, check CheckFunc) (result CheckResult) {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	result = CheckResult{Name: name, Status: StatusPass}
+	defer func() {
+		result.DurationMS = float64(time.Since(start).Microseconds()) / 1000
+	}()
+
+	// The check runs in its own goroutine, so a panic must be recovered there
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("check panicked: %v", r)
+			}
+		}()
+		done <- check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			result.Status = StatusFail
+			result.Error = err.Error()
+		}
+	case <-ctx.Done():
+		result.Status = StatusFail
+		result.Error = "check timed out"
+	}
+
+	return result
+}
+
+// LivenessHandler serves the liveness probe
+func LivenessHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return response.Success(c, "Service is alive", Liveness())
+	}
+}
+
+// ReadinessHandler serves the readiness probe, answering 503 when any check fails
+func ReadinessHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		report := Readiness(c.UserContext())
+		if report.Status != StatusPass {
+			return response.ServiceUnavailable(c, "Service is not ready", report)
+		}
+		return response.Success(c, "Service is ready", report)
+	}
+}
@@ -0,0 +1,22 @@
+package auth
+
+import (
+	"housing-api/internal/config"
+	"housing-api/pkg/metrics"
+	"housing-api/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireAdmin allows only users listed in ADMIN_EMAILS; it must run after JWTMiddleware
+func RequireAdmin(cfg *config.Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		email, _ := c.Locals("userEmail").(string)
+		if !cfg.IsAdmin(email) {
+			metrics.IncAuthFailure("not_admin")
+			return response.Forbidden(c, "Admin access required", nil)
+		}
+
+		return c.Next()
+	}
+}
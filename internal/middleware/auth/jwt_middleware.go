@@ -3,38 +3,40 @@ package auth
 import (
 	"strings"
 
-	"housing-api/internal/config"
 	"housing-api/internal/services"
+	"housing-api/pkg/metrics"
 	"housing-api/pkg/response"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 // JWTMiddleware validates JWT tokens
-func JWTMiddleware(cfg *config.Config) fiber.Handler {
-	authService := services.NewAuthService(cfg)
-
+func JWTMiddleware(authService *services.AuthService) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Get Authorization header
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
+			metrics.IncAuthFailure("missing_header")
 			return response.Unauthorized(c, "Authorization header is required", nil)
 		}
 
 		// Check if it's a Bearer token
 		if !strings.HasPrefix(authHeader, "Bearer ") {
+			metrics.IncAuthFailure("invalid_format")
 			return response.Unauthorized(c, "Invalid authorization header format", nil)
 		}
 
 		// Extract token
 		token := strings.TrimPrefix(authHeader, "Bearer ")
 		if token == "" {
+			metrics.IncAuthFailure("missing_token")
 			return response.Unauthorized(c, "Token is required", nil)
 		}
 
 		// Validate token
 		claims, err := authService.ValidateToken(token)
 		if err != nil {
+			metrics.IncAuthFailure("invalid_token")
 			return response.Unauthorized(c, "Invalid token", err)
 		}
 
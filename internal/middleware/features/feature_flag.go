@@ -0,0 +1,25 @@
+package features
+
+import (
+	"housing-api/internal/config"
+	"housing-api/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Require hides the routes behind it unless the named feature flag is enabled. The flag is read
+// from the active configuration on every request so reloads take effect immediately.
+func Require(cfg *config.Config, name string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		current := config.Active()
+		if current == nil {
+			current = cfg
+		}
+
+		if !current.FeatureEnabled(name) {
+			return response.NotFound(c, "Resource not found", nil)
+		}
+
+		return c.Next()
+	}
+}
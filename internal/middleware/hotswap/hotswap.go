@@ -0,0 +1,29 @@
+package hotswap
+
+import (
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler is a middleware whose implementation can be replaced while requests are in flight
+type Handler struct {
+	current atomic.Pointer[fiber.Handler]
+}
+
+// New creates a swappable middleware starting with handler
+func New(handler fiber.Handler) *Handler {
+	h := &Handler{}
+	h.Swap(handler)
+	return h
+}
+
+// Swap atomically replaces the middleware used by subsequent requests
+func (h *Handler) Swap(handler fiber.Handler) {
+	h.current.Store(&handler)
+}
+
+// Handle runs the current middleware
+func (h *Handler) Handle(c *fiber.Ctx) error {
+	return (*h.current.Load())(c)
+}
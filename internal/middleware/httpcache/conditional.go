@@ -0,0 +1,82 @@
+package httpcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Conditional adds a strong ETag computed from the response payload, a Last-Modified time from
+// lastModified and the given Cache-Control policy to successful GET/HEAD responses, and answers
+// 304 Not Modified when If-None-Match (or, without it, If-Modified-Since) shows the client's copy
+// is current. Because the ETag is derived from the payload, it changes whenever the data does.
+func Conditional(cacheControl string, lastModified func() time.Time) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+			return c.Next()
+		}
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+		if c.Response().StatusCode() != fiber.StatusOK {
+			return nil
+		}
+
+		etag := ETag(c.Response().Body())
+		c.Set(fiber.HeaderETag, etag)
+		if cacheControl != "" {
+			c.Set(fiber.HeaderCacheControl, cacheControl)
+		}
+
+		var modified time.Time
+		if lastModified != nil {
+			modified = lastModified().UTC().Truncate(time.Second)
+		}
+		if !modified.IsZero() {
+			c.Set(fiber.HeaderLastModified, modified.Format(http.TimeFormat))
+		}
+
+		if notModified(c, etag, modified) {
+			c.Context().ResetBody()
+			c.Status(fiber.StatusNotModified)
+		}
+
+		return nil
+	}
+}
+
+// ETag returns a strong entity tag for a response payload
+func ETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// notModified evaluates the request's conditional headers; If-None-Match takes precedence (RFC 9110 §13.2.2)
+func notModified(c *fiber.Ctx, etag string, modified time.Time) bool {
+	if match := c.Get(fiber.HeaderIfNoneMatch); match != "" {
+		return etagMatches(match, etag)
+	}
+
+	if since := c.Get(fiber.HeaderIfModifiedSince); since != "" && !modified.IsZero() {
+		t, err := http.ParseTime(since)
+		return err == nil && !modified.After(t)
+	}
+
+	return false
+}
+
+// etagMatches applies the weak comparison If-None-Match requires against a list of tags or *
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -18,7 +18,7 @@ func RequestLogger() fiber.Handler {
 
 		// Log request
 		duration := time.Since(start)
-		logger.Info("HTTP Request",
+		logger.InfoContext(c.UserContext(), "HTTP Request",
 			"method", c.Method(),
 			"path", c.Path(),
 			"status", c.Response().StatusCode(),
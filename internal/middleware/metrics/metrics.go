@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"housing-api/pkg/metrics"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Metrics records request counts and latency per route, method and status
+func Metrics() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		// Process request
+		err := c.Next()
+
+		// Errors are turned into responses by the app error handler after this middleware returns
+		status := c.Response().StatusCode()
+		var fiberErr *fiber.Error
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+
+		// Label by route pattern rather than raw path to keep cardinality bounded. A request no
+		// route matched fails with the router's 404 (or 405) error and is left on the route of the
+		// last middleware it passed, so it gets a label of its own.
+		route := c.Route().Path
+		if fiberErr != nil && (fiberErr.Code == fiber.StatusNotFound || fiberErr.Code == fiber.StatusMethodNotAllowed) {
+			route = "unmatched"
+		}
+
+		metrics.ObserveRequest(route, c.Method(), status, time.Since(start))
+
+		return err
+	}
+}
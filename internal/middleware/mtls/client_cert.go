@@ -0,0 +1,39 @@
+package mtls
+
+import (
+	"housing-api/pkg/metrics"
+	"housing-api/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// SubjectLocalsKey is the fiber locals key holding the verified client certificate subject
+	SubjectLocalsKey = "clientCertSubject"
+	// CommonNameLocalsKey is the fiber locals key holding the verified client certificate common name
+	CommonNameLocalsKey = "clientCertCommonName"
+)
+
+// RequireClientCert only lets through requests that presented a client certificate verified
+// against the configured client CAs, exposing its subject to downstream handlers
+func RequireClientCert() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		state := c.Context().TLSConnectionState()
+		if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+			metrics.IncAuthFailure("missing_client_cert")
+			return response.Unauthorized(c, "A verified client certificate is required", nil)
+		}
+
+		cert := state.VerifiedChains[0][0]
+		c.Locals(SubjectLocalsKey, cert.Subject.String())
+		c.Locals(CommonNameLocalsKey, cert.Subject.CommonName)
+
+		return c.Next()
+	}
+}
+
+// ClientSubject returns the verified client certificate subject, or an empty string
+func ClientSubject(c *fiber.Ctx) string {
+	subject, _ := c.Locals(SubjectLocalsKey).(string)
+	return subject
+}
@@ -2,28 +2,26 @@ package ratelimit
 
 import (
 	"housing-api/internal/config"
+	"housing-api/pkg/metrics"
+	"housing-api/pkg/response"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/limiter"
 )
 
-// RateLimiter creates a rate limiting middleware
-func RateLimiter(cfg *config.Config) fiber.Handler {
+// RateLimiter creates a rate limiting middleware counting requests in storage. Rebuilding it over
+// the same storage changes the limits without resetting the counters.
+func RateLimiter(cfg *config.Config, storage fiber.Storage) fiber.Handler {
 	return limiter.New(limiter.Config{
 		Max:        cfg.RateLimitMaxRequests,
 		Expiration: cfg.RateLimitWindowMS,
+		Storage:    storage,
 		KeyGenerator: func(c *fiber.Ctx) string {
 			return c.IP()
 		},
 		LimitReached: func(c *fiber.Ctx) error {
-			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
-				"success": false,
-				"error": fiber.Map{
-					"code":    fiber.StatusTooManyRequests,
-					"message": "Rate limit exceeded",
-				},
-				"data": nil,
-			})
+			metrics.IncRateLimitRejections()
+			return response.TooManyRequests(c, "Rate limit exceeded", nil)
 		},
 	})
 }
@@ -0,0 +1,90 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often Set drops expired entries, so keys of clients that stopped sending
+// requests don't accumulate
+const sweepInterval = time.Minute
+
+// Storage is an in-memory fiber.Storage for rate limit counters. Limiters built over the same
+// Storage share their counters, so a changed policy keeps counting where the old one left off.
+type Storage struct {
+	mu        sync.Mutex
+	entries   map[string]storageEntry
+	lastSweep time.Time
+}
+
+// storageEntry is a stored value and when it expires; a zero expiry never expires
+type storageEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewStorage creates an empty counter store
+func NewStorage() *Storage {
+	return &Storage{entries: make(map[string]storageEntry), lastSweep: time.Now()}
+}
+
+// Get returns the value stored for key, or nil when there is none or it has expired
+func (s *Storage) Get(key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok || entry.expired(time.Now()) {
+		return nil, nil
+	}
+	return entry.value, nil
+}
+
+// Set stores a copy of value under key for exp, or without expiry when exp is zero
+func (s *Storage) Set(key string, value []byte, exp time.Duration) error {
+	now := time.Now()
+	entry := storageEntry{value: append([]byte(nil), value...)}
+	if exp > 0 {
+		entry.expires = now.Add(exp)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = entry
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		for key, entry := range s.entries {
+			if entry.expired(now) {
+				delete(s.entries, key)
+			}
+		}
+		s.lastSweep = now
+	}
+	return nil
+}
+
+// Delete removes the value stored for key
+func (s *Storage) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+// Reset removes every stored value
+func (s *Storage) Reset() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = make(map[string]storageEntry)
+	return nil
+}
+
+// Close does nothing; the store lives as long as the process
+func (s *Storage) Close() error {
+	return nil
+}
+
+// expired reports whether the entry has expired at now
+func (e storageEntry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
@@ -0,0 +1,57 @@
+package requestid
+
+import (
+	"strings"
+
+	"housing-api/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const (
+	// HeaderName is the header used to accept and echo request IDs
+	HeaderName = "X-Request-ID"
+	// CorrelationHeaderName is accepted as a fallback when clients use correlation IDs
+	CorrelationHeaderName = "X-Correlation-ID"
+	// LocalsKey is the fiber locals key holding the request ID
+	LocalsKey = "requestID"
+
+	maxRequestIDLength = 128
+)
+
+// RequestID accepts an incoming X-Request-ID (or generates one), stores it in the
+// request context and echoes it back in the response headers
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID := c.Get(HeaderName)
+		if requestID == "" {
+			requestID = c.Get(CorrelationHeaderName)
+		}
+		if !isValid(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		// Header values are only valid for the lifetime of the handler, so keep a copy
+		requestID = strings.Clone(requestID)
+
+		c.Locals(LocalsKey, requestID)
+		c.SetUserContext(logger.WithRequestID(c.UserContext(), requestID))
+		c.Set(HeaderName, requestID)
+
+		return c.Next()
+	}
+}
+
+// isValid rejects empty, oversized or non-printable request IDs so they are safe to log and echo
+func isValid(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range requestID {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
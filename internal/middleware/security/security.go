@@ -0,0 +1,59 @@
+package security
+
+import (
+	"strings"
+
+	"housing-api/internal/config"
+	"housing-api/internal/middleware/requestid"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/helmet"
+)
+
+// requiredHeaders are always allowed so request IDs and trace context survive cross-origin calls
+var requiredHeaders = []string{"traceparent", "tracestate", requestid.HeaderName}
+
+// CORS builds the CORS middleware from config. Origins may use a leading wildcard subdomain,
+// e.g. https://*.worksquare.com; credentialed requests require explicit origins.
+func CORS(cfg *config.Config) fiber.Handler {
+	return cors.New(cors.Config{
+		AllowOrigins:     strings.Join(cfg.CORSAllowOrigins, ","),
+		AllowMethods:     strings.Join(cfg.CORSAllowMethods, ","),
+		AllowHeaders:     strings.Join(withRequired(cfg.CORSAllowHeaders, requiredHeaders...), ","),
+		ExposeHeaders:    strings.Join(withRequired(cfg.CORSExposeHeaders, requestid.HeaderName, fiber.HeaderLink), ","),
+		AllowCredentials: cfg.CORSAllowCredentials,
+		MaxAge:           int(cfg.CORSMaxAge.Seconds()),
+	})
+}
+
+// Headers builds the security-header middleware (CSP, HSTS, Referrer-Policy and helmet defaults).
+// HSTS is only sent over HTTPS.
+func Headers(cfg *config.Config) fiber.Handler {
+	return helmet.New(helmet.Config{
+		ContentSecurityPolicy: cfg.SecurityCSP,
+		CSPReportOnly:         cfg.SecurityCSPReportOnly,
+		HSTSMaxAge:            int(cfg.SecurityHSTSMaxAge.Seconds()),
+		HSTSExcludeSubdomains: !cfg.SecurityHSTSIncludeSubdomains,
+		HSTSPreloadEnabled:    cfg.SecurityHSTSPreload,
+		ReferrerPolicy:        cfg.SecurityReferrerPolicy,
+	})
+}
+
+// withRequired appends any required headers missing from headers (case-insensitively)
+func withRequired(headers []string, required ...string) []string {
+	result := append([]string(nil), headers...)
+	for _, header := range required {
+		found := false
+		for _, existing := range headers {
+			if strings.EqualFold(existing, header) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, header)
+		}
+	}
+	return result
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Facet names
+const (
+	FacetPropertyType = "property_type"
+	FacetListingType  = "listing_type"
+	FacetCity         = "city"
+	FacetArea         = "area"
+	FacetBedrooms     = "bedrooms"
+	FacetPrice        = "price"
+)
+
+// FacetNames lists the available facets, in the order they are documented
+var FacetNames = []string{FacetPropertyType, FacetListingType, FacetCity, FacetArea, FacetBedrooms, FacetPrice}
+
+// FacetCount is the number of listings with one value of a facet. Price buckets also carry the
+// min_price/max_price bounds that select them; the last bucket has no upper bound.
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+	Min   *int   `json:"min,omitempty"`
+	Max   *int   `json:"max,omitempty"`
+}
+
+// Facets maps facet names to their value counts
+type Facets map[string][]FacetCount
+
+// ParseFacets parses a facets parameter: comma-separated facet names, or "all" for every facet.
+// Unknown names are reported as a ValidationError.
+func ParseFacets(spec string) ([]string, error) {
+	var names []string
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(spec, ",") {
+		name := strings.ToLower(strings.TrimSpace(part))
+		switch {
+		case name == "":
+			continue
+		case name == "all":
+			return FacetNames, nil
+		case !isFacet(name):
+			return nil, ValidationError{
+				Field:   "facets",
+				Message: fmt.Sprintf("unknown facet %q; allowed facets are %s, or all", name, strings.Join(FacetNames, ", ")),
+				Value:   spec,
+			}
+		case !seen[name]:
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	return names, nil
+}
+
+func isFacet(name string) bool {
+	for _, facet := range FacetNames {
+		if facet == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package models
+
+import "housing-api/pkg/filterexpr"
+
+// ListingFilterFields are the fields a filter expression may reference
+var ListingFilterFields = filterexpr.Schema{
+	"id":            filterexpr.Number,
+	"title":         filterexpr.String,
+	"price":         filterexpr.Number,
+	"bedrooms":      filterexpr.Number,
+	"bathrooms":     filterexpr.Number,
+	"location":      filterexpr.String,
+	"area":          filterexpr.String,
+	"city":          filterexpr.String,
+	"property_type": filterexpr.String,
+	"listing_type":  filterexpr.String,
+}
+
+// ListingFilterColumns maps filter expression fields to the columns of a SQL listings table, for
+// backends that compile expressions with filterexpr.SQL instead of evaluating them in memory.
+// price_numeric is 0 for a listing without a valid price, which FilterValue treats as no price.
+var ListingFilterColumns = map[string]string{
+	"id":            "id",
+	"title":         "title",
+	"price":         "NULLIF(price_numeric, 0)",
+	"bedrooms":      "bedrooms",
+	"bathrooms":     "bathrooms",
+	"location":      "location",
+	"area":          "area",
+	"city":          "city",
+	"property_type": "property_type",
+	"listing_type":  "listing_type",
+}
+
+// ParseFilterExpression parses a filter= expression against ListingFilterFields; syntax and
+// type errors are reported as a ValidationError naming the column
+func ParseFilterExpression(expression string) (filterexpr.Node, error) {
+	node, err := filterexpr.Parse(expression, ListingFilterFields)
+	if err != nil {
+		return nil, ValidationError{Field: "filter", Message: err.Error(), Value: expression}
+	}
+	return node, nil
+}
+
+// FilterValue returns the listing's value for a filter expression field; a listing without a
+// valid price has no price value
+func (l *Listing) FilterValue(field string) (filterexpr.Value, bool) {
+	switch field {
+	case "id":
+		return filterexpr.NumberValue(float64(l.ID)), true
+	case "title":
+		return filterexpr.StringValue(l.Title), true
+	case "price":
+		price := l.GetPriceNumeric()
+		return filterexpr.NumberValue(price), price > 0
+	case "bedrooms":
+		return filterexpr.NumberValue(float64(l.Bedrooms)), true
+	case "bathrooms":
+		return filterexpr.NumberValue(float64(l.Bathrooms)), true
+	case "location":
+		return filterexpr.StringValue(l.Location), true
+	case "area":
+		return filterexpr.StringValue(l.GetArea()), true
+	case "city":
+		return filterexpr.StringValue(l.GetCity()), true
+	case "property_type":
+		return filterexpr.StringValue(l.GetPropertyType()), true
+	case "listing_type":
+		return filterexpr.StringValue(l.GetListingType()), true
+	}
+	return filterexpr.Value{}, false
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"housing-api/pkg/geo"
+)
+
+// MaxRadiusKm bounds radius_km; Nigeria is about 1,100 km across
+const MaxRadiusKm = 2000
+
+// GeoFilter is the spatial part of a ListingFilter, parsed
+type GeoFilter struct {
+	// Near is the point distances are measured from; nil without near=
+	Near *geo.Point
+
+	// RadiusKm limits results to that distance from Near; 0 for no limit
+	RadiusKm float64
+
+	// Box limits results to a latitude/longitude rectangle; nil without bounds
+	Box *geo.BoundingBox
+}
+
+// ParseGeo parses and validates the near, radius_km and latitude/longitude bound filters.
+// Problems are reported as a ValidationError naming the parameter.
+func (f ListingFilter) ParseGeo() (GeoFilter, error) {
+	var filter GeoFilter
+
+	if near := strings.TrimSpace(f.Near); near != "" {
+		point, err := ParsePoint(near)
+		if err != nil {
+			return GeoFilter{}, ValidationError{Field: "near", Message: err.Error(), Value: f.Near}
+		}
+		filter.Near = &point
+	}
+
+	if f.RadiusKm != nil {
+		radius := *f.RadiusKm
+		switch {
+		case filter.Near == nil:
+			return GeoFilter{}, ValidationError{Field: "radius_km", Message: "radius_km requires near", Value: formatFloat(radius)}
+		case math.IsNaN(radius) || radius <= 0 || radius > MaxRadiusKm:
+			return GeoFilter{}, ValidationError{Field: "radius_km", Message: fmt.Sprintf("radius_km must be greater than 0 and at most %d", MaxRadiusKm), Value: formatFloat(radius)}
+		}
+		filter.RadiusKm = radius
+	}
+
+	if f.MinLat != nil || f.MaxLat != nil || f.MinLng != nil || f.MaxLng != nil {
+		box := geo.World
+		for _, bound := range []struct {
+			name     string
+			value    *float64
+			target   *float64
+			min, max float64
+		}{
+			{"min_lat", f.MinLat, &box.MinLat, -90, 90},
+			{"max_lat", f.MaxLat, &box.MaxLat, -90, 90},
+			{"min_lng", f.MinLng, &box.MinLng, -180, 180},
+			{"max_lng", f.MaxLng, &box.MaxLng, -180, 180},
+		} {
+			if bound.value == nil {
+				continue
+			}
+			if math.IsNaN(*bound.value) || *bound.value < bound.min || *bound.value > bound.max {
+				return GeoFilter{}, ValidationError{Field: bound.name, Message: fmt.Sprintf("%s must be between %g and %g", bound.name, bound.min, bound.max), Value: formatFloat(*bound.value)}
+			}
+			*bound.target = *bound.value
+		}
+		if box.MinLat > box.MaxLat {
+			return GeoFilter{}, ValidationError{Field: "min_lat", Message: "min_lat must not exceed max_lat", Value: formatFloat(box.MinLat)}
+		}
+		if box.MinLng > box.MaxLng {
+			return GeoFilter{}, ValidationError{Field: "min_lng", Message: "min_lng must not exceed max_lng", Value: formatFloat(box.MinLng)}
+		}
+		filter.Box = &box
+	}
+
+	return filter, nil
+}
+
+// ParsePoint parses a "lat,lng" point in decimal degrees
+func ParsePoint(spec string) (geo.Point, error) {
+	latText, lngText, found := strings.Cut(spec, ",")
+	if !found {
+		return geo.Point{}, fmt.Errorf("near must be \"lat,lng\", e.g. 6.4478,3.4723")
+	}
+	lat, latErr := strconv.ParseFloat(strings.TrimSpace(latText), 64)
+	lng, lngErr := strconv.ParseFloat(strings.TrimSpace(lngText), 64)
+	if latErr != nil || lngErr != nil {
+		return geo.Point{}, fmt.Errorf("near must be \"lat,lng\" in decimal degrees, e.g. 6.4478,3.4723")
+	}
+
+	point := geo.Point{Lat: lat, Lng: lng}
+	if !point.Valid() {
+		return geo.Point{}, fmt.Errorf("near latitude must be between -90 and 90 and longitude between -180 and 180")
+	}
+	return point, nil
+}
+
+// Bounded reports whether the filter limits results to a region
+func (g GeoFilter) Bounded() bool {
+	return g.RadiusKm > 0 || g.Box != nil
+}
+
+// Region returns the box enclosing every point the filter can match
+func (g GeoFilter) Region() geo.BoundingBox {
+	region := geo.World
+	if g.RadiusKm > 0 {
+		region = region.Intersect(geo.Around(*g.Near, g.RadiusKm))
+	}
+	if g.Box != nil {
+		region = region.Intersect(*g.Box)
+	}
+	return region
+}
+
+// Matches reports whether a listing lies within the filter's radius and box; listings without
+// coordinates only match an unbounded filter
+func (g GeoFilter) Matches(l *Listing) bool {
+	if !g.Bounded() {
+		return true
+	}
+	point, ok := l.Coordinates()
+	if !ok {
+		return false
+	}
+	if g.Box != nil && !g.Box.Contains(point) {
+		return false
+	}
+	return g.RadiusKm == 0 || geo.DistanceKm(*g.Near, point) <= g.RadiusKm
+}
+
+// formatFloat formats a parameter value for a ValidationError
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// Coordinates returns the listing's location, if it has one
+func (l *Listing) Coordinates() (geo.Point, bool) {
+	if l.Latitude == nil || l.Longitude == nil {
+		return geo.Point{}, false
+	}
+	return geo.Point{Lat: *l.Latitude, Lng: *l.Longitude}, true
+}
@@ -0,0 +1,26 @@
+package models
+
+// GeocodingReport summarises how the loaded listings got their coordinates
+type GeocodingReport struct {
+	// Total is the number of listings loaded
+	Total int `json:"total"`
+
+	// WithCoordinates counts the listings whose source data carried coordinates
+	WithCoordinates int `json:"with_coordinates"`
+
+	// Geocoded counts the listings located from the gazetteer, by precision
+	Geocoded map[string]int `json:"geocoded"`
+
+	// Unresolved lists the listings whose location the gazetteer doesn't know; they have no
+	// coordinates and never match a radius or bounding box search
+	Unresolved []UnresolvedListing `json:"unresolved"`
+
+	// Places is the number of places in the gazetteer
+	Places int `json:"places"`
+}
+
+// UnresolvedListing is a listing the geocoder could not locate
+type UnresolvedListing struct {
+	ID       int    `json:"id"`
+	Location string `json:"location"`
+}
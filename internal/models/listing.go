@@ -3,6 +3,8 @@ package models
 import (
 	"strconv"
 	"strings"
+
+	"housing-api/pkg/filterexpr"
 )
 
 // Listing represents a housing listing
@@ -15,6 +17,17 @@ type Listing struct {
 	Location   string   `json:"location"`
 	Status     []string `json:"status"`
 	Image      string   `json:"image"`
+
+	// Latitude and Longitude locate the listing, when known
+	Latitude   *float64 `json:"latitude,omitempty"`
+	Longitude  *float64 `json:"longitude,omitempty"`
+
+	// GeoPrecision is the level of the gazetteer place ("neighbourhood", "city" or "state") the
+	// coordinates were geocoded from; empty when they came with the listing
+	GeoPrecision string `json:"geo_precision,omitempty"`
+
+	// DistanceKm is the distance from the near= point of the query that returned the listing
+	DistanceKm *float64 `json:"distance_km,omitempty"`
 }
 
 // GetPropertyType returns the property type from the status array
@@ -53,6 +66,23 @@ func (l *Listing) GetPriceNumeric() float64 {
 	return price
 }
 
+// GetNormalizedPrice returns the price as a yearly amount so weekly and nightly prices compare
+// with annual rents; 0 when the listing has no valid price
+func (l *Listing) GetNormalizedPrice() float64 {
+	price := l.GetPriceNumeric()
+	if _, period, found := strings.Cut(l.Price, "/"); found {
+		switch strings.ToLower(strings.TrimSpace(period)) {
+		case "night", "day":
+			price *= 365
+		case "week":
+			price *= 52
+		case "month":
+			price *= 12
+		}
+	}
+	return price
+}
+
 // GetCity extracts the city from the location string
 func (l *Listing) GetCity() string {
 	parts := strings.Split(l.Location, ",")
@@ -71,23 +101,135 @@ func (l *Listing) GetArea() string {
 	return l.Location
 }
 
-// ListingFilter represents filtering options for listings
+// CSVHeader returns the flattened column names used when listings are exported as CSV
+func (l Listing) CSVHeader() []string {
+	return []string{
+		"id", "title", "price", "price_numeric", "bedrooms", "bathrooms",
+		"location", "area", "city", "property_type", "listing_type", "status", "image",
+		"latitude", "longitude", "distance_km",
+	}
+}
+
+// CSVRecord returns the listing's values in CSVHeader order
+func (l Listing) CSVRecord() []string {
+	return []string{
+		strconv.Itoa(l.ID),
+		l.Title,
+		l.Price,
+		strconv.FormatFloat(l.GetPriceNumeric(), 'f', -1, 64),
+		strconv.Itoa(l.Bedrooms),
+		strconv.Itoa(l.Bathrooms),
+		l.Location,
+		l.GetArea(),
+		l.GetCity(),
+		l.GetPropertyType(),
+		l.GetListingType(),
+		strings.Join(l.Status, "|"),
+		l.Image,
+		formatOptional(l.Latitude),
+		formatOptional(l.Longitude),
+		formatOptional(l.DistanceKm),
+	}
+}
+
+// formatOptional formats an optional number for CSV, empty when absent
+func formatOptional(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
+
+// ListingFilter represents filtering options for listings. Location, property type and city take
+// several values and match any of them; their exclude_ forms drop listings matching any value
+// exactly. Property types and cities may be comma-separated, but locations contain commas
+// ("Lekki, Lagos"), so several locations must be repeated.
 type ListingFilter struct {
-	Location     string `json:"location" query:"location"`
-	PropertyType string `json:"property_type" query:"property_type"`
-	MinPrice     *int   `json:"min_price" query:"min_price"`
-	MaxPrice     *int   `json:"max_price" query:"max_price"`
-	MinBedrooms  *int   `json:"min_bedrooms" query:"min_bedrooms"`
-	MaxBedrooms  *int   `json:"max_bedrooms" query:"max_bedrooms"`
-	MinBathrooms *int   `json:"min_bathrooms" query:"min_bathrooms"`
-	MaxBathrooms *int   `json:"max_bathrooms" query:"max_bathrooms"`
-	City         string `json:"city" query:"city"`
-}
-
-// PaginationQuery represents pagination parameters
+	Location     []string `json:"location,omitempty" query:"location"`
+	PropertyType []string `json:"property_type,omitempty" query:"property_type"`
+	MinPrice     *int     `json:"min_price" query:"min_price"`
+	MaxPrice     *int     `json:"max_price" query:"max_price"`
+	MinBedrooms  *int     `json:"min_bedrooms" query:"min_bedrooms"`
+	MaxBedrooms  *int     `json:"max_bedrooms" query:"max_bedrooms"`
+	MinBathrooms *int     `json:"min_bathrooms" query:"min_bathrooms"`
+	MaxBathrooms *int     `json:"max_bathrooms" query:"max_bathrooms"`
+	City         []string `json:"city,omitempty" query:"city"`
+
+	ExcludeLocation     []string `json:"exclude_location,omitempty" query:"exclude_location"`
+	ExcludePropertyType []string `json:"exclude_property_type,omitempty" query:"exclude_property_type"`
+	ExcludeCity         []string `json:"exclude_city,omitempty" query:"exclude_city"`
+
+	// Near is a "lat,lng" point; with RadiusKm it limits results to that distance and it enables
+	// sorting by distance. The Min/Max latitude and longitude bounds limit results to a box.
+	Near     string   `json:"near,omitempty" query:"near"`
+	RadiusKm *float64 `json:"radius_km,omitempty" query:"radius_km"`
+	MinLat   *float64 `json:"min_lat,omitempty" query:"min_lat"`
+	MaxLat   *float64 `json:"max_lat,omitempty" query:"max_lat"`
+	MinLng   *float64 `json:"min_lng,omitempty" query:"min_lng"`
+	MaxLng   *float64 `json:"max_lng,omitempty" query:"max_lng"`
+
+	// Query is the free-text search, set by the search endpoint; it drives relevance sorting
+	Query string `json:"q,omitempty" query:"q"`
+
+	// Filter is an expression such as (city:Lagos OR city:Abuja) AND price<=3000000, combined
+	// with the other filters; see filterexpr.Parse for the grammar
+	Filter string `json:"filter,omitempty" query:"filter"`
+
+	// Expression is an already parsed filter expression, for callers that build one from another
+	// syntax (OData $filter); it combines with Filter and the other filters
+	Expression filterexpr.Node `json:"-" query:"-"`
+
+	// Facets names the facet counts to return with the results; it doesn't filter
+	Facets string `json:"facets,omitempty" query:"facets"`
+}
+
+// Normalize splits comma-separated values of the property type and city filters, drops blank
+// values of the multi-value filters and trims the filter expression
+func (f *ListingFilter) Normalize() {
+	f.Filter = strings.TrimSpace(f.Filter)
+	for _, values := range []*[]string{
+		&f.PropertyType, &f.City, &f.ExcludePropertyType, &f.ExcludeCity,
+	} {
+		*values = splitValues(*values)
+	}
+	for _, values := range []*[]string{&f.Location, &f.ExcludeLocation} {
+		*values = trimValues(*values)
+	}
+}
+
+// splitValues splits each value on commas, trimming and dropping blanks; nil when none remain
+func splitValues(values []string) []string {
+	var split []string
+	for _, value := range values {
+		split = append(split, strings.Split(value, ",")...)
+	}
+	return trimValues(split)
+}
+
+// trimValues trims each value and drops blanks; nil when none remain
+func trimValues(values []string) []string {
+	var trimmed []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			trimmed = append(trimmed, value)
+		}
+	}
+	return trimmed
+}
+
+// PaginationQuery represents pagination parameters. A cursor, when present, takes precedence over page.
 type PaginationQuery struct {
-	Page  int `json:"page" query:"page" validate:"min=1"`
-	Limit int `json:"limit" query:"limit" validate:"min=1,max=100"`
+	Page   int    `json:"page" query:"page" validate:"min=1"`
+	Limit  int    `json:"limit" query:"limit" validate:"min=1,max=100"`
+	Cursor string `json:"cursor" query:"cursor"`
+	Sort   string `json:"sort" query:"sort"`
+}
+
+// SortPosition locates a listing within a result ordering: its sort key values, then its ID
+// as the tie-breaker
+type SortPosition struct {
+	Keys []float64 `json:"k,omitempty"`
+	ID   int       `json:"i"`
 }
 
 // SetDefaults sets default values for pagination
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"housing-api/pkg/odata"
+)
+
+// ResoNamespace is the schema namespace of the RESO Data Dictionary
+const ResoNamespace = "org.reso.metadata"
+
+// ResoField maps a RESO Data Dictionary field of the Property resource onto a listing. Filter
+// and Sort name the filter expression field and sort field it translates to, empty when the
+// field can't be filtered or sorted on. Nullable fields may have no value.
+type ResoField struct {
+	Name     string
+	Type     string
+	Filter   string
+	Sort     string
+	Nullable bool
+	Value    func(l *Listing) any
+}
+
+// ResoPropertyFields lists the RESO fields of the Property resource, in $metadata order
+var ResoPropertyFields = []ResoField{
+	{Name: "ListingKey", Type: "Edm.String", Value: func(l *Listing) any { return strconv.Itoa(l.ID) }},
+	{Name: "ListingKeyNumeric", Type: "Edm.Int64", Filter: "id", Sort: SortID, Value: func(l *Listing) any { return l.ID }},
+	{Name: "ListPrice", Type: "Edm.Decimal", Filter: "price", Sort: SortListPrice, Nullable: true, Value: func(l *Listing) any {
+		if price := l.GetPriceNumeric(); price > 0 {
+			return price
+		}
+		return nil
+	}},
+	{Name: "LeaseAmountFrequency", Type: "Edm.String", Value: func(l *Listing) any { return l.GetLeaseAmountFrequency() }},
+	{Name: "BedroomsTotal", Type: "Edm.Int32", Filter: "bedrooms", Sort: SortBedrooms, Value: func(l *Listing) any { return l.Bedrooms }},
+	{Name: "BathroomsTotalInteger", Type: "Edm.Int32", Filter: "bathrooms", Sort: SortBathrooms, Value: func(l *Listing) any { return l.Bathrooms }},
+	{Name: "PropertyType", Type: "Edm.String", Value: func(l *Listing) any { return "ResidentialLease" }},
+	{Name: "PropertySubType", Type: "Edm.String", Filter: "property_type", Value: func(l *Listing) any { return l.GetPropertyType() }},
+	{Name: "StandardStatus", Type: "Edm.String", Value: func(l *Listing) any { return "Active" }},
+	{Name: "UnparsedAddress", Type: "Edm.String", Filter: "location", Value: func(l *Listing) any { return l.Location }},
+	{Name: "SubdivisionName", Type: "Edm.String", Filter: "area", Value: func(l *Listing) any { return l.GetArea() }},
+	{Name: "City", Type: "Edm.String", Filter: "city", Value: func(l *Listing) any { return l.GetCity() }},
+	{Name: "Country", Type: "Edm.String", Value: func(l *Listing) any { return "NG" }},
+	{Name: "Latitude", Type: "Edm.Decimal", Nullable: true, Value: func(l *Listing) any { return optionalValue(l.Latitude) }},
+	{Name: "Longitude", Type: "Edm.Decimal", Nullable: true, Value: func(l *Listing) any { return optionalValue(l.Longitude) }},
+	{Name: "PublicRemarks", Type: "Edm.String", Filter: "title", Value: func(l *Listing) any { return l.Title }},
+}
+
+// FindResoField looks up a RESO Property field by its case-sensitive name
+func FindResoField(name string) (ResoField, bool) {
+	for _, field := range ResoPropertyFields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return ResoField{}, false
+}
+
+// ResoFilterFields returns the RESO fields usable in $filter, mapped onto filter expression fields
+func ResoFilterFields() map[string]odata.Field {
+	fields := make(map[string]odata.Field)
+	for _, field := range ResoPropertyFields {
+		if field.Filter != "" {
+			fields[field.Name] = odata.Field{Name: field.Filter, Type: ListingFilterFields[field.Filter]}
+		}
+	}
+	return fields
+}
+
+// ResoEntityType describes the Property resource for the $metadata document
+func ResoEntityType() odata.EntityType {
+	entityType := odata.EntityType{Name: "Property", Key: "ListingKey"}
+	for _, field := range ResoPropertyFields {
+		entityType.Properties = append(entityType.Properties, odata.Property{
+			Name:     field.Name,
+			Type:     field.Type,
+			Nullable: field.Nullable,
+		})
+	}
+	return entityType
+}
+
+// ResoProperty returns the listing as a RESO Property with the selected fields, or all fields
+// when selected is empty
+func (l *Listing) ResoProperty(selected []string) map[string]any {
+	property := make(map[string]any)
+	for _, field := range ResoPropertyFields {
+		if len(selected) == 0 || slices.Contains(selected, field.Name) {
+			property[field.Name] = field.Value(l)
+		}
+	}
+	return property
+}
+
+// GetLeaseAmountFrequency returns the RESO LeaseAmountFrequency of the price: Daily for nightly
+// or daily prices, Weekly, Monthly, and Annually for prices without a period
+func (l *Listing) GetLeaseAmountFrequency() string {
+	_, period, _ := strings.Cut(l.Price, "/")
+	switch strings.ToLower(strings.TrimSpace(period)) {
+	case "night", "day":
+		return "Daily"
+	case "week":
+		return "Weekly"
+	case "month":
+		return "Monthly"
+	}
+	return "Annually"
+}
+
+// ResoSort translates $orderby items into a listing Sort; fields that can't be sorted on are
+// reported as a ValidationError. ListPrice sorts by the listed price, as its value and $filter use.
+func ResoSort(orderBy []odata.OrderBy) (Sort, error) {
+	var parts []string
+	for _, item := range orderBy {
+		field, ok := FindResoField(item.Property)
+		if !ok || field.Sort == "" {
+			return nil, ValidationError{Field: "$orderby", Message: "unknown or non-sortable property " + item.Property, Value: item.Property}
+		}
+		if item.Descending {
+			parts = append(parts, "-"+field.Sort)
+		} else {
+			parts = append(parts, field.Sort)
+		}
+	}
+	return ParseSort(strings.Join(parts, ","), false)
+}
+
+// optionalValue returns the value of an optional number, nil when absent
+func optionalValue(value *float64) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
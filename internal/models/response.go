@@ -10,22 +10,41 @@ type APIResponse struct {
 }
 
 type ErrorInfo struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-	Details string `json:"details,omitempty"`
+	Code      int    `json:"code"`
+	Message   string `json:"message"`
+	Details   string `json:"details,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
 }
 
 // MetaInfo represents metadata for responses (e.g., pagination)
 type MetaInfo struct {
-	Page       int   `json:"page,omitempty"`
-	Limit      int   `json:"limit,omitempty"`
-	Total      int64 `json:"total,omitempty"`
-	TotalPages int   `json:"total_pages,omitempty"`
+	Page       int              `json:"page,omitempty"`
+	Limit      int              `json:"limit,omitempty"`
+	Total      int64            `json:"total"`
+	TotalPages int              `json:"total_pages"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+	Links      *PaginationLinks `json:"links,omitempty"`
+}
+
+// PaginationLinks are ready-to-follow page URLs that preserve the request's filters
+type PaginationLinks struct {
+	Self  string `json:"self"`
+	First string `json:"first"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last,omitempty"`
 }
 
 type PaginatedResponse struct {
 	Items []interface{} `json:"items"`
 	Meta  MetaInfo      `json:"meta"`
+
+	// Facets counts the filtered results by property type, city, price range and so on, when
+	// requested with the facets parameter
+	Facets Facets `json:"facets,omitempty"`
+
+	// DidYouMean suggests a corrected search query when the search returned nothing
+	DidYouMean string `json:"did_you_mean,omitempty"`
 }
 
 type ValidationError struct {
@@ -34,7 +53,12 @@ type ValidationError struct {
 	Value   string `json:"value,omitempty"`
 }
 
+// Error lets a single ValidationError be returned (and matched with errors.As) as an error
+func (e ValidationError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
 type ValidationErrorResponse struct {
 	Message string            `json:"message"`
 	Errors  []ValidationError `json:"errors"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,146 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultSort is the ordering used when none is requested: ascending by ID
+const DefaultSort = "id"
+
+// Sort fields
+const (
+	SortPrice     = "price"
+	SortListPrice = "list_price"
+	SortBedrooms  = "bedrooms"
+	SortBathrooms = "bathrooms"
+	SortID        = "id"
+	SortNewest    = "newest"
+	SortRelevance = "relevance"
+	SortDistance  = "distance"
+)
+
+// SortField describes a field results can be sorted by
+type SortField struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	SearchOnly  bool   `json:"search_only,omitempty"`
+	NearOnly    bool   `json:"near_only,omitempty"`
+}
+
+// SortFields lists the allowed sort fields, in the order they are documented
+var SortFields = []SortField{
+	{Name: SortPrice, Description: "Price normalized to a yearly amount; listings without a price sort last"},
+	{Name: SortListPrice, Description: "Price as listed, whatever its period; listings without a price sort last"},
+	{Name: SortBedrooms, Description: "Number of bedrooms"},
+	{Name: SortBathrooms, Description: "Number of bathrooms"},
+	{Name: SortID, Description: "Listing ID"},
+	{Name: SortNewest, Description: "Most recently added first (-newest for oldest first)"},
+	{Name: SortRelevance, Description: "Best search match first (-relevance for weakest first)", SearchOnly: true},
+	{Name: SortDistance, Description: "Nearest to the near= point first; listings without coordinates sort last", NearOnly: true},
+}
+
+// SortKey is one component of a sort: a field and its direction
+type SortKey struct {
+	Field      string
+	Descending bool
+}
+
+// Sort is an ordering by one or more keys, ties broken by ascending ID
+type Sort []SortKey
+
+// ParseSort parses a sort parameter such as "-price,bedrooms": comma-separated fields, each
+// optionally prefixed with "-" for descending order. Unknown, repeated or (outside search)
+// relevance fields are reported as a ValidationError.
+func ParseSort(spec string, search bool) (Sort, error) {
+	var sort Sort
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		key := SortKey{Field: strings.ToLower(strings.TrimPrefix(part, "-")), Descending: strings.HasPrefix(part, "-")}
+		field, ok := findSortField(key.Field)
+		switch {
+		case !ok:
+			return nil, ValidationError{
+				Field:   "sort",
+				Message: fmt.Sprintf("unknown sort field %q; allowed fields are %s", key.Field, strings.Join(sortFieldNames(), ", ")),
+				Value:   spec,
+			}
+		case field.SearchOnly && !search:
+			return nil, ValidationError{
+				Field:   "sort",
+				Message: fmt.Sprintf("sort field %q is only available for search", key.Field),
+				Value:   spec,
+			}
+		case seen[key.Field]:
+			return nil, ValidationError{
+				Field:   "sort",
+				Message: fmt.Sprintf("sort field %q is repeated", key.Field),
+				Value:   spec,
+			}
+		}
+
+		seen[key.Field] = true
+		sort = append(sort, key)
+	}
+
+	// Ascending ID is the implicit tie-breaker, so a trailing "id" adds nothing
+	if n := len(sort); n > 0 && sort[n-1] == (SortKey{Field: SortID}) {
+		sort = sort[:n-1]
+	}
+	return sort, nil
+}
+
+// CheckNear reports a ValidationError when the sort needs a near= point and there is none
+func (s Sort) CheckNear(near bool) error {
+	for _, key := range s {
+		if field, _ := findSortField(key.Field); field.NearOnly && !near {
+			return ValidationError{
+				Field:   "sort",
+				Message: fmt.Sprintf("sort field %q requires near", key.Field),
+				Value:   s.String(),
+			}
+		}
+	}
+	return nil
+}
+
+// String returns the canonical form of the sort, DefaultSort when empty
+func (s Sort) String() string {
+	if len(s) == 0 {
+		return DefaultSort
+	}
+
+	parts := make([]string, len(s))
+	for i, key := range s {
+		parts[i] = key.Field
+		if key.Descending {
+			parts[i] = "-" + key.Field
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// findSortField looks up an allowed sort field by name
+func findSortField(name string) (SortField, bool) {
+	for _, field := range SortFields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return SortField{}, false
+}
+
+// sortFieldNames returns the names of the allowed sort fields
+func sortFieldNames() []string {
+	names := make([]string, len(SortFields))
+	for i, field := range SortFields {
+		names[i] = field.Name
+	}
+	return names
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"housing-api/pkg/geocode"
+)
+
+// GazetteerRepository holds the gazetteer used to geocode listings, backed by an editable JSON file
+type GazetteerRepository struct {
+	mu        sync.RWMutex
+	filePath  string
+	gazetteer *geocode.Gazetteer
+	onChange  []func(*geocode.Gazetteer)
+}
+
+// NewGazetteerRepository loads the gazetteer at filePath; a missing file means no places
+func NewGazetteerRepository(filePath string) (*GazetteerRepository, error) {
+	repo := &GazetteerRepository{filePath: filePath}
+	if err := repo.Reload(); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// Get returns the current gazetteer
+func (r *GazetteerRepository) Get() *geocode.Gazetteer {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.gazetteer
+}
+
+// OnChange registers fn to be called with the new gazetteer whenever it is reloaded
+func (r *GazetteerRepository) OnChange(fn func(*geocode.Gazetteer)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onChange = append(r.onChange, fn)
+}
+
+// Reload re-reads the gazetteer file; an invalid file leaves the current gazetteer in place
+func (r *GazetteerRepository) Reload() error {
+	gazetteer := geocode.File{States: []geocode.Place{}}
+
+	file, err := os.ReadFile(r.filePath)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return fmt.Errorf("failed to read gazetteer file: %w", err)
+	default:
+		if err := json.Unmarshal(file, &gazetteer); err != nil {
+			return fmt.Errorf("failed to unmarshal gazetteer: %w", err)
+		}
+	}
+
+	if err := gazetteer.Validate(); err != nil {
+		return fmt.Errorf("invalid gazetteer file: %w", err)
+	}
+
+	r.replace(geocode.NewGazetteer(gazetteer))
+	return nil
+}
+
+// replace swaps in a new gazetteer and notifies change listeners
+func (r *GazetteerRepository) replace(gazetteer *geocode.Gazetteer) {
+	r.mu.Lock()
+	r.gazetteer = gazetteer
+	listeners := r.onChange
+	r.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(gazetteer)
+	}
+}
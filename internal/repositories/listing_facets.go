@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"housing-api/internal/models"
+)
+
+// facetFilters maps each facet to the filter criteria it ignores when counting; listing type has
+// no filter of its own
+var facetFilters = map[string]filterGroup{
+	models.FacetPropertyType: filterPropertyType,
+	models.FacetListingType:  0,
+	models.FacetCity:         filterCity,
+	models.FacetArea:         filterLocation,
+	models.FacetBedrooms:     filterBedrooms,
+	models.FacetPrice:        filterPrice,
+}
+
+// GetFacets counts the listings matching filter (and its search query) by each named facet.
+// Each facet ignores its own filter criterion (disjunctive faceting), so with city=Lagos the
+// city facet still counts the other cities. Prices fall into buckets bounded by the ascending
+// priceBuckets; listings without a valid price are left out of the price facet.
+func (r *ListingRepository) GetFacets(filter models.ListingFilter, names []string, priceBuckets []int) models.Facets {
+	r.mu.RLock()
+	listings, searchIndex, synonyms := r.listings, r.searchIndex, r.synonyms
+	r.mu.RUnlock()
+
+	var hits map[int]float64
+	if filter.Query != "" {
+		hits = searchIndex.SearchAny(synonyms.Expand(filter.Query))
+	}
+	prepared := r.prepareFilter(filter)
+
+	counts := make([]map[string]int, len(names))
+	for i := range counts {
+		counts[i] = make(map[string]int)
+	}
+
+	for _, listing := range listings {
+		if filter.Query != "" {
+			if _, ok := hits[listing.ID]; !ok {
+				continue
+			}
+		}
+
+		failed := r.failedFilters(listing, prepared)
+		for i, name := range names {
+			if failed&^facetFilters[name] != 0 {
+				continue
+			}
+			if value := facetValue(listing, name, priceBuckets); value != "" {
+				counts[i][value]++
+			}
+		}
+	}
+
+	facets := make(models.Facets, len(names))
+	for i, name := range names {
+		switch name {
+		case models.FacetPrice:
+			facets[name] = priceFacet(counts[i], priceBuckets)
+		case models.FacetBedrooms:
+			facets[name] = sortedFacet(counts[i], func(a, b string) bool {
+				x, _ := strconv.Atoi(a)
+				y, _ := strconv.Atoi(b)
+				return x < y
+			})
+		default:
+			facets[name] = sortedFacet(counts[i], nil)
+		}
+	}
+	return facets
+}
+
+// facetValue returns listing's value for a facet, or "" when it has none
+func facetValue(listing models.Listing, name string, priceBuckets []int) string {
+	switch name {
+	case models.FacetPropertyType:
+		return listing.GetPropertyType()
+	case models.FacetListingType:
+		return listing.GetListingType()
+	case models.FacetCity:
+		return listing.GetCity()
+	case models.FacetArea:
+		return listing.GetArea()
+	case models.FacetBedrooms:
+		return strconv.Itoa(listing.Bedrooms)
+	case models.FacetPrice:
+		price := listing.GetPriceNumeric()
+		if price <= 0 {
+			return ""
+		}
+		bucket := sort.Search(len(priceBuckets), func(i int) bool { return price < float64(priceBuckets[i]) })
+		return priceBucketLabel(bucket, priceBuckets)
+	}
+	return ""
+}
+
+// sortedFacet orders facet counts by less, or by descending count then value when less is nil
+func sortedFacet(counts map[string]int, less func(a, b string) bool) []models.FacetCount {
+	facet := make([]models.FacetCount, 0, len(counts))
+	for value, count := range counts {
+		facet = append(facet, models.FacetCount{Value: value, Count: count})
+	}
+
+	sort.Slice(facet, func(i, j int) bool {
+		if less != nil {
+			return less(facet[i].Value, facet[j].Value)
+		}
+		if facet[i].Count != facet[j].Count {
+			return facet[i].Count > facet[j].Count
+		}
+		return facet[i].Value < facet[j].Value
+	})
+	return facet
+}
+
+// priceFacet lists every price bucket in order, including empty ones, with the min_price and
+// max_price values that select it
+func priceFacet(counts map[string]int, priceBuckets []int) []models.FacetCount {
+	facet := make([]models.FacetCount, 0, len(priceBuckets)+1)
+	for bucket := 0; bucket <= len(priceBuckets); bucket++ {
+		value := priceBucketLabel(bucket, priceBuckets)
+		count := models.FacetCount{Value: value, Count: counts[value], Min: new(int)}
+		if bucket > 0 {
+			*count.Min = priceBuckets[bucket-1]
+		}
+		if bucket < len(priceBuckets) {
+			count.Max = new(int)
+			*count.Max = priceBuckets[bucket] - 1
+		}
+		facet = append(facet, count)
+	}
+	return facet
+}
+
+// priceBucketLabel names a price bucket by its inclusive bounds, e.g. "1000000-1999999" or
+// "5000000+" for the last
+func priceBucketLabel(bucket int, priceBuckets []int) string {
+	low := 0
+	if bucket > 0 {
+		low = priceBuckets[bucket-1]
+	}
+	if bucket == len(priceBuckets) {
+		return fmt.Sprintf("%d+", low)
+	}
+	return fmt.Sprintf("%d-%d", low, priceBuckets[bucket]-1)
+}
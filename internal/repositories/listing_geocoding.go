@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"housing-api/internal/models"
+	"housing-api/pkg/geocode"
+)
+
+// geocodeListings returns a copy of listings in which those without coordinates take the centroid
+// of their area from gazetteer, and a report of how each got its coordinates. Listings whose area
+// and city the gazetteer doesn't know are left without coordinates.
+func geocodeListings(listings []models.Listing, gazetteer *geocode.Gazetteer) ([]models.Listing, models.GeocodingReport) {
+	report := models.GeocodingReport{
+		Total:      len(listings),
+		Geocoded:   make(map[string]int),
+		Unresolved: []models.UnresolvedListing{},
+		Places:     gazetteer.Len(),
+	}
+
+	geocoded := make([]models.Listing, len(listings))
+	copy(geocoded, listings)
+	for i := range geocoded {
+		listing := &geocoded[i]
+		if _, ok := listing.Coordinates(); ok {
+			report.WithCoordinates++
+			continue
+		}
+
+		match, ok := gazetteer.Resolve(listing.GetArea(), listing.GetCity())
+		if !ok {
+			report.Unresolved = append(report.Unresolved, models.UnresolvedListing{ID: listing.ID, Location: listing.Location})
+			continue
+		}
+		lat, lng := match.Point.Lat, match.Point.Lng
+		listing.Latitude, listing.Longitude = &lat, &lng
+		listing.GeoPrecision = match.Precision
+		report.Geocoded[match.Precision]++
+	}
+	return geocoded, report
+}
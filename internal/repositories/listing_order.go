@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"math"
+	"sort"
+
+	"housing-api/internal/models"
+	"housing-api/pkg/geo"
+)
+
+// listingOrder is a result ordering: listings compare by their keys in turn (ascending), then by
+// ID. Descending keys are expressed by negating the value.
+type listingOrder struct {
+	name string
+	keys func(listing models.Listing) []float64
+
+	// cacheable orders depend only on the listing data, so their index can be reused until the
+	// next load; relevance and distance orders depend on the query
+	cacheable bool
+}
+
+// newOrder builds the ordering for sort; scores are the text-search relevance of each listing ID
+// and origin the point distances are measured from
+func newOrder(s models.Sort, scores map[int]float64, origin *geo.Point) listingOrder {
+	order := listingOrder{name: s.String(), cacheable: true}
+	for _, key := range s {
+		if key.Field == models.SortRelevance || key.Field == models.SortDistance {
+			order.cacheable = false
+		}
+	}
+
+	order.keys = func(listing models.Listing) []float64 {
+		if len(s) == 0 {
+			return nil
+		}
+		keys := make([]float64, len(s))
+		for i, key := range s {
+			keys[i] = sortValue(listing, key, scores, origin)
+		}
+		return keys
+	}
+	return order
+}
+
+// sortValue returns listing's ascending sort value for key
+func sortValue(listing models.Listing, key models.SortKey, scores map[int]float64, origin *geo.Point) float64 {
+	var value float64
+	switch key.Field {
+	case models.SortPrice:
+		value = listing.GetNormalizedPrice()
+		if value <= 0 {
+			// Listings without a price sort last in either direction
+			return math.MaxFloat64
+		}
+	case models.SortListPrice:
+		value = listing.GetPriceNumeric()
+		if value <= 0 {
+			return math.MaxFloat64
+		}
+	case models.SortBedrooms:
+		value = float64(listing.Bedrooms)
+	case models.SortBathrooms:
+		value = float64(listing.Bathrooms)
+	case models.SortID:
+		value = float64(listing.ID)
+	case models.SortNewest:
+		// Newest and relevance put the highest value first unless reversed
+		value = -float64(listing.ID)
+	case models.SortRelevance:
+		value = -scores[listing.ID]
+	case models.SortDistance:
+		point, ok := listing.Coordinates()
+		if !ok || origin == nil {
+			// Listings without coordinates sort last in either direction
+			return math.MaxFloat64
+		}
+		value = geo.DistanceKm(*origin, point)
+	}
+
+	if key.Descending {
+		return -value
+	}
+	return value
+}
+
+// positioned is an entry of a sorted index: a listing's position and its offset in the listing slice
+type positioned struct {
+	position models.SortPosition
+	index    int
+}
+
+// buildIndex sorts the listings' positions for order, limited to the IDs in only when it is set
+func buildIndex(listings []models.Listing, order listingOrder, only map[int]float64) []positioned {
+	all := make([]int, len(listings))
+	for i := range all {
+		all[i] = i
+	}
+	return buildIndexOf(listings, order, all, only)
+}
+
+// buildIndexOf sorts the positions of the listings at the given offsets for order, limited to the
+// IDs in only when it is set
+func buildIndexOf(listings []models.Listing, order listingOrder, offsets []int, only map[int]float64) []positioned {
+	index := make([]positioned, 0, len(offsets))
+	for _, i := range offsets {
+		listing := listings[i]
+		if _, ok := only[listing.ID]; only != nil && !ok {
+			continue
+		}
+		index = append(index, positioned{
+			position: models.SortPosition{Keys: order.keys(listing), ID: listing.ID},
+			index:    i,
+		})
+	}
+
+	sort.SliceStable(index, func(i, j int) bool {
+		return comparePositions(index[i].position, index[j].position) < 0
+	})
+	return index
+}
+
+// comparePositions orders positions by their keys, then ID
+func comparePositions(a, b models.SortPosition) int {
+	for i := 0; i < len(a.Keys) && i < len(b.Keys); i++ {
+		switch {
+		case a.Keys[i] < b.Keys[i]:
+			return -1
+		case a.Keys[i] > b.Keys[i]:
+			return 1
+		}
+	}
+
+	switch {
+	case a.ID < b.ID:
+		return -1
+	case a.ID > b.ID:
+		return 1
+	}
+	return 0
+}
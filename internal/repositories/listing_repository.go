@@ -1,20 +1,81 @@
 package repositories
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"housing-api/internal/models"
 	"housing-api/internal/utils"
+	"housing-api/pkg/filterexpr"
+	"housing-api/pkg/geo"
+	"housing-api/pkg/geocode"
+	"housing-api/pkg/metrics"
+	"housing-api/pkg/search"
+	"housing-api/pkg/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // ListingRepository handles listing data operations
 type ListingRepository struct {
+	mu       sync.RWMutex
 	listings []models.Listing
 	filePath string
+	onChange []func()
+
+	// rebuildMu serialises rebuilds, from reading their inputs to swapping in the result, so a
+	// slow rebuild can't overwrite a newer one with stale listings or a stale gazetteer
+	rebuildMu sync.Mutex
+
+	// modTime is when the served data last changed. It advances on every change, even one that
+	// restores an older file, so Last-Modified never repeats for different data.
+	modTime time.Time
+
+	// version increments on every replace; orders caches sorted indexes of the current version,
+	// searchIndex is the full-text index over it and completions the autocomplete index
+	version     uint64
+	orders      map[string][]positioned
+	searchIndex *search.Index
+	completions *search.PrefixIndex
+
+	// grid indexes the positions in listings of the listings with coordinates by geohash cell
+	grid *geo.Grid
+
+	// synonyms expand search queries and text filters
+	synonyms *search.Synonyms
+
+	// source is the listing data as read from disk; listings is source with the coordinates
+	// gazetteer geocoded for the listings without them, as summarised by geocoding
+	source    []models.Listing
+	gazetteer *geocode.Gazetteer
+	geocoding models.GeocodingReport
+}
+
+// Completion kinds, in the order they rank when otherwise tied
+const (
+	CompletionArea         = "area"
+	CompletionCity         = "city"
+	CompletionPropertyType = "property_type"
+	CompletionTitle        = "title"
+)
+
+// gridPrecision is the geohash precision of the spatial index: cells of about 4.9 x 4.9 km,
+// near the size of a neighbourhood
+const gridPrecision = 5
+
+// searchFields are the listing fields covered by full-text search; title matches count most
+var searchFields = []search.Field{
+	{Name: "title", Weight: 2},
+	{Name: "location", Weight: 1.5},
+	{Name: "property_type", Weight: 1},
 }
 
 func NewListingRepository() (*ListingRepository, error) {
@@ -37,29 +98,200 @@ func NewListingRepository() (*ListingRepository, error) {
 
 // loadListings loads listings from JSON file
 func (r *ListingRepository) loadListings() error {
+	r.rebuildMu.Lock()
+	defer r.rebuildMu.Unlock()
+
+	info, err := os.Stat(r.filePath)
+	if err != nil {
+		return fmt.Errorf("failed to stat listings file: %w", err)
+	}
+
 	file, err := os.ReadFile(r.filePath)
 	if err != nil {
 		return fmt.Errorf("failed to read listings file: %w", err)
 	}
 
-	if err := json.Unmarshal(file, &r.listings); err != nil {
+	var listings []models.Listing
+	if err := json.Unmarshal(file, &listings); err != nil {
 		return fmt.Errorf("failed to unmarshal listings: %w", err)
 	}
 
+	r.replace(listings, info.ModTime())
 	return nil
 }
 
+// replace swaps in a new listing set, geocoding it and rebuilding its indexes, and notifies change
+// listeners. The slice is never modified afterwards, so readers may keep iterating a snapshot
+// without holding the lock. r.rebuildMu must be held.
+func (r *ListingRepository) replace(source []models.Listing, modTime time.Time) {
+	r.mu.RLock()
+	gazetteer := r.gazetteer
+	r.mu.RUnlock()
+
+	listings, geocoding := geocodeListings(source, gazetteer)
+	searchIndex := newSearchIndex(listings)
+	completions := newCompletionIndex(listings)
+	grid := newSpatialIndex(listings)
+
+	r.mu.Lock()
+	r.listings = listings
+	r.source = source
+	r.geocoding = geocoding
+	r.touch(modTime)
+	r.version++
+	r.orders = make(map[string][]positioned)
+	r.searchIndex = searchIndex
+	r.completions = completions
+	r.grid = grid
+	listeners := r.onChange
+	r.mu.Unlock()
+
+	metrics.SetListingsLoaded(len(listings))
+	for _, listener := range listeners {
+		listener()
+	}
+}
+
+// newSearchIndex builds the full-text index for listings
+func newSearchIndex(listings []models.Listing) *search.Index {
+	docs := make([]search.Document, len(listings))
+	for i, listing := range listings {
+		docs[i] = search.Document{
+			ID:     listing.ID,
+			Fields: []string{listing.Title, listing.Location, listing.GetPropertyType()},
+		}
+	}
+	return search.NewIndex(searchFields, docs)
+}
+
+// newSpatialIndex builds the geohash grid over the listings that have coordinates
+func newSpatialIndex(listings []models.Listing) *geo.Grid {
+	grid := geo.NewGrid(gridPrecision)
+	for i := range listings {
+		if point, ok := listings[i].Coordinates(); ok {
+			grid.Add(i, point)
+		}
+	}
+	return grid
+}
+
+// newCompletionIndex builds the autocomplete index over listing areas, cities, property types and
+// titles, counting the listings behind each
+func newCompletionIndex(listings []models.Listing) *search.PrefixIndex {
+	type value struct{ kind, text string }
+	counts := make(map[value]int)
+	var order []value
+
+	for _, listing := range listings {
+		for _, v := range []value{
+			{CompletionArea, listing.GetArea()},
+			{CompletionCity, listing.GetCity()},
+			{CompletionPropertyType, listing.GetPropertyType()},
+			{CompletionTitle, listing.Title},
+		} {
+			if v.text == "" {
+				continue
+			}
+			if counts[v] == 0 {
+				order = append(order, v)
+			}
+			counts[v]++
+		}
+	}
+
+	entries := make([]search.Completion, len(order))
+	for i, v := range order {
+		entries[i] = search.Completion{Text: v.text, Kind: v.kind, Count: counts[v]}
+	}
+	return search.NewPrefixIndex(entries, CompletionArea, CompletionCity, CompletionPropertyType, CompletionTitle)
+}
+
+// snapshot returns the current listings
+func (r *ListingRepository) snapshot() []models.Listing {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.listings
+}
+
+// SetSynonyms replaces the synonyms applied to search queries and text filters, advancing the
+// modification time and notifying change listeners since results may differ
+func (r *ListingRepository) SetSynonyms(synonyms *search.Synonyms) {
+	r.mu.Lock()
+	r.synonyms = synonyms
+	r.touch(time.Now())
+	listeners := r.onChange
+	r.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener()
+	}
+}
+
+// SetGazetteer replaces the gazetteer listings without coordinates are geocoded from and
+// geocodes the loaded listings again, advancing the modification time and notifying change
+// listeners
+func (r *ListingRepository) SetGazetteer(gazetteer *geocode.Gazetteer) {
+	r.rebuildMu.Lock()
+	defer r.rebuildMu.Unlock()
+
+	r.mu.Lock()
+	r.gazetteer = gazetteer
+	source := r.source
+	r.mu.Unlock()
+
+	r.replace(source, time.Now())
+}
+
+// GeocodingReport summarises how the loaded listings got their coordinates. The report is
+// shared and must not be modified.
+func (r *ListingRepository) GeocodingReport() models.GeocodingReport {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.geocoding
+}
+
+// OnChange registers fn to be called whenever listings are reloaded or mutated
+func (r *ListingRepository) OnChange(fn func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onChange = append(r.onChange, fn)
+}
+
+// LastModified returns when the served listing data last changed
+func (r *ListingRepository) LastModified() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.modTime
+}
+
+// touch records a change made at t, moving modTime to t or, when t isn't later, one second past
+// it (Last-Modified has one second resolution); r.mu must be held for writing
+func (r *ListingRepository) touch(t time.Time) {
+	t = t.Truncate(time.Second)
+	if !t.After(r.modTime) {
+		t = r.modTime.Add(time.Second)
+	}
+	r.modTime = t
+}
+
 // ReloadListings reloads listings from JSON file (useful for updates)
 func (r *ListingRepository) ReloadListings() error {
-	return r.loadListings()
+	if err := r.loadListings(); err != nil {
+		return err
+	}
+
+	metrics.IncDataReloads()
+	return nil
 }
 
 // GetAll returns all listings with optional filtering
 func (r *ListingRepository) GetAll(filter models.ListingFilter) ([]models.Listing, error) {
+	listings := r.snapshot()
+	prepared := r.prepareFilter(filter)
 	var filtered []models.Listing
 
-	for _, listing := range r.listings {
-		if r.matchesFilter(listing, filter) {
+	for _, listing := range listings {
+		if r.matchesFilter(listing, prepared) {
 			filtered = append(filtered, listing)
 		}
 	}
@@ -68,7 +300,7 @@ func (r *ListingRepository) GetAll(filter models.ListingFilter) ([]models.Listin
 }
 
 func (r *ListingRepository) GetByID(id int) (*models.Listing, error) {
-	for _, listing := range r.listings {
+	for _, listing := range r.snapshot() {
 		if listing.ID == id {
 			return &listing, nil
 		}
@@ -76,41 +308,162 @@ func (r *ListingRepository) GetByID(id int) (*models.Listing, error) {
 	return nil, fmt.Errorf("listing with ID %d not found", id)
 }
 
-// GetPaginated returns paginated listings with sorting support
-func (r *ListingRepository) GetPaginated(filter models.ListingFilter, page, limit int) ([]models.Listing, int64, error) {
-	// Get all filtered listings
-	filtered, err := r.GetAll(filter)
-	if err != nil {
-		return nil, 0, err
-	}
+// GetPaginated returns a page of listings matching filter in sort order, the total number of
+// matches and, when more results follow, the position of the page's last listing
+func (r *ListingRepository) GetPaginated(ctx context.Context, filter models.ListingFilter, sorting models.Sort, page, limit int) ([]models.Listing, int64, *models.SortPosition, error) {
+	return r.GetOffset(ctx, filter, sorting, (page-1)*limit, limit)
+}
 
-	total := int64(len(filtered))
+// GetOffset returns up to limit listings matching filter in sort order after skipping the first
+// offset of them, the total number of matches and, when more results follow, the position of the
+// last listing returned
+func (r *ListingRepository) GetOffset(ctx context.Context, filter models.ListingFilter, sorting models.Sort, offset, limit int) ([]models.Listing, int64, *models.SortPosition, error) {
+	_, span := tracing.Start(ctx, "ListingRepository.GetOffset")
+	defer span.End()
+
+	listings, index, hits := r.ordered(sorting, filter)
+	items, total, next := r.collect(listings, index, hits, 0, offset, limit, filter, true)
+
+	span.SetAttributes(
+		attribute.Int("listings.scanned", len(index)),
+		attribute.Int64("listings.matched", total),
+	)
+	return items, total, next, nil
+}
+
+// GetAfter returns up to limit listings matching filter that follow position after in sort order
+// (keyset pagination) and the position to continue from. It walks only from the cursor to the
+// first match past the page; Count gives the total number of matches.
+func (r *ListingRepository) GetAfter(ctx context.Context, filter models.ListingFilter, sorting models.Sort, after models.SortPosition, limit int) ([]models.Listing, *models.SortPosition, error) {
+	_, span := tracing.Start(ctx, "ListingRepository.GetAfter")
+	defer span.End()
+
+	if len(after.Keys) != len(sorting) {
+		return nil, nil, fmt.Errorf("cursor does not match the %q ordering", sorting.String())
+	}
+	listings, index, hits := r.ordered(sorting, filter)
 
-	// Sort by ID for consistent pagination
-	sort.Slice(filtered, func(i, j int) bool {
-		return filtered[i].ID < filtered[j].ID
+	// Binary search for the first listing after the cursor
+	start := sort.Search(len(index), func(i int) bool {
+		return comparePositions(index[i].position, after) > 0
 	})
+	items, matched, next := r.collect(listings, index, hits, start, 0, limit, filter, false)
 
-	// Calculate pagination
-	offset := (page - 1) * limit
-	if offset >= len(filtered) {
-		return []models.Listing{}, total, nil
+	span.SetAttributes(
+		attribute.Int("listings.start", start),
+		attribute.Int64("listings.matched", matched),
+	)
+	return items, next, nil
+}
+
+// Count returns the number of listings matching filter, walking the index ordered by sorting
+func (r *ListingRepository) Count(ctx context.Context, filter models.ListingFilter, sorting models.Sort) int64 {
+	_, span := tracing.Start(ctx, "ListingRepository.Count")
+	defer span.End()
+
+	listings, index, hits := r.ordered(sorting, filter)
+	_, total, _ := r.collect(listings, index, hits, 0, 0, 0, filter, true)
+
+	span.SetAttributes(
+		attribute.Int("listings.scanned", len(index)),
+		attribute.Int64("listings.matched", total),
+	)
+	return total
+}
+
+// collect walks index from position start, gathering up to limit listings that match filter (and,
+// when hits is set, the text query) after skipping the first skip of them, and returns them with
+// the number of matches seen. With all set it walks to the end, counting every match; otherwise
+// it stops at the first match past the page.
+func (r *ListingRepository) collect(listings []models.Listing, index []positioned, hits map[int]float64, start, skip, limit int, filter models.ListingFilter, all bool) ([]models.Listing, int64, *models.SortPosition) {
+	items := []models.Listing{}
+	var matched int64
+	var last models.SortPosition
+	more := false
+	prepared := r.prepareFilter(filter)
+
+	for _, entry := range index[start:] {
+		listing := listings[entry.index]
+		if _, ok := hits[listing.ID]; hits != nil && !ok {
+			continue
+		}
+		if !r.matchesFilter(listing, prepared) {
+			continue
+		}
+		matched++
+
+		switch {
+		case skip > 0:
+			skip--
+		case len(items) < limit:
+			if prepared.geo.Near != nil {
+				if point, ok := listing.Coordinates(); ok {
+					distance := math.Round(geo.DistanceKm(*prepared.geo.Near, point)*100) / 100
+					listing.DistanceKm = &distance
+				}
+			}
+			items = append(items, listing)
+			last = entry.position
+		default:
+			more = true
+		}
+		if more && !all {
+			break
+		}
+	}
+
+	if !more || len(items) == 0 {
+		return items, matched, nil
+	}
+	return items, matched, &last
+}
+
+// ordered returns the current listings, the sorted index to walk for sorting and, for a text
+// query, the matching listings' relevance scores (nil without a query). Indexes of orders that
+// depend only on the data are cached until the next load; relevance and distance orders index
+// just the matches. A filter bounded to a region indexes only the listings in the grid cells
+// overlapping it, so a radius search doesn't visit listings elsewhere.
+func (r *ListingRepository) ordered(sorting models.Sort, filter models.ListingFilter) ([]models.Listing, []positioned, map[int]float64) {
+	r.mu.RLock()
+	listings, version, searchIndex, synonyms, grid := r.listings, r.version, r.searchIndex, r.synonyms, r.grid
+	index := r.orders[sorting.String()]
+	r.mu.RUnlock()
+
+	var hits map[int]float64
+	if filter.Query != "" {
+		hits = searchIndex.SearchAny(synonyms.Expand(filter.Query))
+	}
+
+	// The service validates the spatial filters; invalid ones are ignored here
+	spatial, _ := filter.ParseGeo()
+	order := newOrder(sorting, hits, spatial.Near)
+	if spatial.Bounded() {
+		return listings, buildIndexOf(listings, order, grid.Candidates(spatial.Region()), hits), hits
+	}
+	if !order.cacheable {
+		return listings, buildIndex(listings, order, hits), hits
+	}
+	if index != nil {
+		return listings, index, hits
 	}
 
-	end := offset + limit
-	if end > len(filtered) {
-		end = len(filtered)
+	index = buildIndex(listings, order, nil)
+	r.mu.Lock()
+	if r.version == version {
+		r.orders[order.name] = index
 	}
+	r.mu.Unlock()
 
-	return filtered[offset:end], total, nil
+	return listings, index, hits
 }
 
 // GetUniqueLocations returns all unique locations (cities)
 func (r *ListingRepository) GetUniqueLocations() []string {
+	listings := r.snapshot()
 	locationMap := make(map[string]bool)
 	var locations []string
 
-	for _, listing := range r.listings {
+	for _, listing := range listings {
 		city := listing.GetCity()
 		if city != "" && !locationMap[city] {
 			locationMap[city] = true
@@ -125,10 +478,11 @@ func (r *ListingRepository) GetUniqueLocations() []string {
 
 // GetUniquePropertyTypes returns all unique property types
 func (r *ListingRepository) GetUniquePropertyTypes() []string {
+	listings := r.snapshot()
 	typeMap := make(map[string]bool)
 	var types []string
 
-	for _, listing := range r.listings {
+	for _, listing := range listings {
 		propertyType := listing.GetPropertyType()
 		if propertyType != "" && !typeMap[propertyType] {
 			typeMap[propertyType] = true
@@ -143,14 +497,15 @@ func (r *ListingRepository) GetUniquePropertyTypes() []string {
 
 // GetPriceRange returns the minimum and maximum prices in the dataset
 func (r *ListingRepository) GetPriceRange() (float64, float64) {
-	if len(r.listings) == 0 {
+	listings := r.snapshot()
+	if len(listings) == 0 {
 		return 0, 0
 	}
 
-	minPrice := r.listings[0].GetPriceNumeric()
+	minPrice := listings[0].GetPriceNumeric()
 	maxPrice := minPrice
 
-	for _, listing := range r.listings {
+	for _, listing := range listings {
 		price := listing.GetPriceNumeric()
 		if price > 0 { // Only consider valid prices
 			if price < minPrice {
@@ -167,14 +522,15 @@ func (r *ListingRepository) GetPriceRange() (float64, float64) {
 
 // GetBedroomRange returns the minimum and maximum number of bedrooms
 func (r *ListingRepository) GetBedroomRange() (int, int) {
-	if len(r.listings) == 0 {
+	listings := r.snapshot()
+	if len(listings) == 0 {
 		return 0, 0
 	}
 
-	minBedrooms := r.listings[0].Bedrooms
+	minBedrooms := listings[0].Bedrooms
 	maxBedrooms := minBedrooms
 
-	for _, listing := range r.listings {
+	for _, listing := range listings {
 		if listing.Bedrooms < minBedrooms {
 			minBedrooms = listing.Bedrooms
 		}
@@ -188,14 +544,15 @@ func (r *ListingRepository) GetBedroomRange() (int, int) {
 
 // GetBathroomRange returns the minimum and maximum number of bathrooms
 func (r *ListingRepository) GetBathroomRange() (int, int) {
-	if len(r.listings) == 0 {
+	listings := r.snapshot()
+	if len(listings) == 0 {
 		return 0, 0
 	}
 
-	minBathrooms := r.listings[0].Bathrooms
+	minBathrooms := listings[0].Bathrooms
 	maxBathrooms := minBathrooms
 
-	for _, listing := range r.listings {
+	for _, listing := range listings {
 		if listing.Bathrooms < minBathrooms {
 			minBathrooms = listing.Bathrooms
 		}
@@ -209,9 +566,10 @@ func (r *ListingRepository) GetBathroomRange() (int, int) {
 
 // GetListingsByPropertyType returns listings grouped by property type
 func (r *ListingRepository) GetListingsByPropertyType() map[string][]models.Listing {
+	listings := r.snapshot()
 	grouped := make(map[string][]models.Listing)
 
-	for _, listing := range r.listings {
+	for _, listing := range listings {
 		propertyType := listing.GetPropertyType()
 		if propertyType != "" {
 			grouped[propertyType] = append(grouped[propertyType], listing)
@@ -223,9 +581,10 @@ func (r *ListingRepository) GetListingsByPropertyType() map[string][]models.List
 
 // GetListingsByCity returns listings grouped by city
 func (r *ListingRepository) GetListingsByCity() map[string][]models.Listing {
+	listings := r.snapshot()
 	grouped := make(map[string][]models.Listing)
 
-	for _, listing := range r.listings {
+	for _, listing := range listings {
 		city := listing.GetCity()
 		if city != "" {
 			grouped[city] = append(grouped[city], listing)
@@ -237,93 +596,217 @@ func (r *ListingRepository) GetListingsByCity() map[string][]models.Listing {
 
 // GetTotalCount returns the total number of listings
 func (r *ListingRepository) GetTotalCount() int {
-	return len(r.listings)
+	return len(r.snapshot())
 }
 
-// SearchListings performs a text-based search across multiple fields
-func (r *ListingRepository) SearchListings(query string) []models.Listing {
-	if query == "" {
-		return r.listings
-	}
+// Complete returns up to limit autocomplete suggestions for prefix
+func (r *ListingRepository) Complete(prefix string, limit int) []search.Completion {
+	r.mu.RLock()
+	completions := r.completions
+	r.mu.RUnlock()
+
+	return completions.Complete(prefix, limit)
+}
+
+// SuggestQuery returns a corrected search query when query matches nothing ("did you mean"),
+// or "" when there is no better query
+func (r *ListingRepository) SuggestQuery(query string) string {
+	r.mu.RLock()
+	searchIndex := r.searchIndex
+	r.mu.RUnlock()
+
+	return searchIndex.Suggest(query)
+}
+
+// preparedFilter is a ListingFilter ready to match many listings: its text values are expanded
+// with synonyms once, up front
+type preparedFilter struct {
+	models.ListingFilter
+	locations     []string
+	cities        []string
+	propertyTypes []string
+
+	excludedLocations     []string
+	excludedCities        []string
+	excludedPropertyTypes []string
 
-	query = strings.ToLower(query)
-	var results []models.Listing
+	// expression is the parsed Filter expression and Expression combined; nil when there is none
+	// or Filter is invalid
+	expression filterexpr.Node
 
-	for _, listing := range r.listings {
-		// Search in title, location, and property type
-		if r.containsQuery(listing.Title, query) ||
-			r.containsQuery(listing.Location, query) ||
-			r.containsQuery(listing.GetPropertyType(), query) {
-			results = append(results, listing)
+	// geo is the parsed radius and bounding box filter
+	geo models.GeoFilter
+}
+
+// prepareFilter expands filter's location, city and property type values, and their exclusions,
+// with the current synonyms
+func (r *ListingRepository) prepareFilter(filter models.ListingFilter) preparedFilter {
+	r.mu.RLock()
+	synonyms := r.synonyms
+	r.mu.RUnlock()
+
+	// The service validates expressions, so a parse error here can only mean a caller skipped
+	// that; the filter then matches nothing rather than everything
+	var expression filterexpr.Node
+	valid := true
+	if filter.Filter != "" {
+		parsed, err := models.ParseFilterExpression(filter.Filter)
+		expression, valid = parsed, err == nil
+	}
+	if filter.Expression != nil && valid {
+		if expression == nil {
+			expression = filter.Expression
+		} else {
+			expression = &filterexpr.And{Left: expression, Right: filter.Expression}
 		}
 	}
+	spatial, _ := filter.ParseGeo()
 
-	return results
+	return preparedFilter{
+		ListingFilter: filter,
+		expression:    expression,
+		geo:           spatial,
+		locations:     expandAll(synonyms, filter.Location),
+		cities:        expandAll(synonyms, filter.City),
+		propertyTypes: expandAll(synonyms, filter.PropertyType),
+
+		excludedLocations:     expandAll(synonyms, filter.ExcludeLocation),
+		excludedCities:        expandAll(synonyms, filter.ExcludeCity),
+		excludedPropertyTypes: expandAll(synonyms, filter.ExcludePropertyType),
+	}
 }
 
-// containsQuery checks if a field contains the search query (case-insensitive)
-func (r *ListingRepository) containsQuery(field, query string) bool {
-	return strings.Contains(strings.ToLower(field), query)
+// expandAll returns every value with its synonyms; any of them may match
+func expandAll(synonyms *search.Synonyms, values []string) []string {
+	var expanded []string
+	for _, value := range values {
+		expanded = append(expanded, synonyms.Expand(value)...)
+	}
+	return expanded
 }
 
-// matchesFilter checks if a listing matches the given filter criteria
-func (r *ListingRepository) matchesFilter(listing models.Listing, filter models.ListingFilter) bool {
-	// Location filter (case-insensitive, partial match in full location string)
-	if filter.Location != "" {
-		if !strings.Contains(strings.ToLower(listing.Location), strings.ToLower(filter.Location)) {
-			return false
+// fuzzyContainsAny reports whether text contains any of queries, tolerating typos
+func fuzzyContainsAny(text string, queries []string) bool {
+	for _, query := range queries {
+		if search.FuzzyContains(text, query) {
+			return true
 		}
 	}
+	return false
+}
 
-	// City filter (case-insensitive, partial match in city name)
-	if filter.City != "" {
-		if !strings.Contains(strings.ToLower(listing.GetCity()), strings.ToLower(filter.City)) {
-			return false
+// namedByAny reports whether any of names, expanded and folded as by expandAll, is exactly one of
+// texts. Exclusions match this way rather than fuzzily, so excluding "Lag" doesn't drop Lagos.
+func namedByAny(names []string, texts ...string) bool {
+	if len(names) == 0 {
+		return false
+	}
+	for _, text := range texts {
+		if slices.Contains(names, strings.Join(search.Words(text), " ")) {
+			return true
 		}
 	}
+	return false
+}
 
-	// Property type filter (case-insensitive, exact match)
-	if filter.PropertyType != "" {
-		if !strings.EqualFold(listing.GetPropertyType(), filter.PropertyType) {
-			return false
-		}
+// filterGroup is a set of ListingFilter criteria, so facet counts can leave out a facet's own
+// criterion
+type filterGroup uint8
+
+const (
+	filterLocation filterGroup = 1 << iota
+	filterCity
+	filterPropertyType
+	filterPrice
+	filterBedrooms
+	filterBathrooms
+	filterExpression
+	filterGeo
+)
+
+// matchesFilter checks if a listing matches the given filter criteria
+func (r *ListingRepository) matchesFilter(listing models.Listing, filter preparedFilter) bool {
+	return r.failedFilters(listing, filter) == 0
+}
+
+// failedFilters returns the criteria of filter that listing does not meet
+func (r *ListingRepository) failedFilters(listing models.Listing, filter preparedFilter) filterGroup {
+	var failed filterGroup
+
+	// Location filter (case-insensitive, partial match in full location string, typo-tolerant,
+	// any value or synonym; excluded values must not name the location, its area or its city)
+	if len(filter.locations) > 0 && !fuzzyContainsAny(listing.Location, filter.locations) {
+		failed |= filterLocation
+	}
+	if namedByAny(filter.excludedLocations, listing.Location, listing.GetArea(), listing.GetCity()) {
+		failed |= filterLocation
+	}
+
+	// City filter (case-insensitive, partial match in city name, typo-tolerant, any value or
+	// synonym; excluded values must not name the city)
+	if len(filter.cities) > 0 && !fuzzyContainsAny(listing.GetCity(), filter.cities) {
+		failed |= filterCity
+	}
+	if namedByAny(filter.excludedCities, listing.GetCity()) {
+		failed |= filterCity
+	}
+
+	// Property type filter (case-insensitive, exact match of any value or synonym; excluded
+	// values must not match)
+	propertyType := listing.GetPropertyType()
+	if len(filter.propertyTypes) > 0 && !namedByAny(filter.propertyTypes, propertyType) {
+		failed |= filterPropertyType
+	}
+	if namedByAny(filter.excludedPropertyTypes, propertyType) {
+		failed |= filterPropertyType
 	}
 
 	// Price range filter
 	price := listing.GetPriceNumeric()
 	if price > 0 { // Only apply price filters to listings with valid prices
 		if filter.MinPrice != nil && price < float64(*filter.MinPrice) {
-			return false
+			failed |= filterPrice
 		}
 		if filter.MaxPrice != nil && price > float64(*filter.MaxPrice) {
-			return false
+			failed |= filterPrice
 		}
 	}
 
 	// Bedrooms range filter
 	if filter.MinBedrooms != nil && listing.Bedrooms < *filter.MinBedrooms {
-		return false
+		failed |= filterBedrooms
 	}
 	if filter.MaxBedrooms != nil && listing.Bedrooms > *filter.MaxBedrooms {
-		return false
+		failed |= filterBedrooms
 	}
 
 	// Bathrooms range filter
 	if filter.MinBathrooms != nil && listing.Bathrooms < *filter.MinBathrooms {
-		return false
+		failed |= filterBathrooms
 	}
 	if filter.MaxBathrooms != nil && listing.Bathrooms > *filter.MaxBathrooms {
-		return false
+		failed |= filterBathrooms
 	}
 
-	return true
+	// Filter expression
+	if (filter.Filter != "" || filter.Expression != nil) && (filter.expression == nil || !filterexpr.Eval(filter.expression, &listing)) {
+		failed |= filterExpression
+	}
+
+	// Radius and bounding box filter
+	if !filter.geo.Matches(&listing) {
+		failed |= filterGeo
+	}
+
+	return failed
 }
 
 // GetSimilarListings returns listings similar to the given listing
 func (r *ListingRepository) GetSimilarListings(targetListing models.Listing, limit int) []models.Listing {
+	listings := r.snapshot()
 	var similar []models.Listing
 
-	for _, listing := range r.listings {
+	for _, listing := range listings {
 		// Skip the same listing
 		if listing.ID == targetListing.ID {
 			continue
@@ -368,7 +851,7 @@ func (r *ListingRepository) isSimilar(listing1, listing2 models.Listing) bool {
 	// Similar price range (within 20%)
 	price1 := listing1.GetPriceNumeric()
 	price2 := listing2.GetPriceNumeric()
-	
+
 	if price1 > 0 && price2 > 0 {
 		priceDiff := (price1 - price2) / price2
 		if priceDiff < -0.2 || priceDiff > 0.2 {
@@ -377,4 +860,4 @@ func (r *ListingRepository) isSimilar(listing1, listing2 models.Listing) bool {
 	}
 
 	return true
-}
\ No newline at end of file
+}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"housing-api/pkg/search"
+)
+
+// SynonymRepository holds the search synonym dictionary, backed by an editable JSON file
+type SynonymRepository struct {
+	mu sync.RWMutex
+
+	// updateMu serialises Save and Reload, so each file write or read is applied in the same order
+	// it happened and a concurrent update can't overwrite a newer dictionary with an older one
+	updateMu sync.Mutex
+
+	filePath string
+	synonyms *search.Synonyms
+	onChange []func(*search.Synonyms)
+}
+
+// NewSynonymRepository loads the dictionary at filePath; a missing file means no synonyms
+func NewSynonymRepository(filePath string) (*SynonymRepository, error) {
+	repo := &SynonymRepository{filePath: filePath}
+	if err := repo.Reload(); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// Get returns the current synonyms
+func (r *SynonymRepository) Get() *search.Synonyms {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.synonyms
+}
+
+// OnChange registers fn to be called with the new synonyms whenever they are reloaded or saved
+func (r *SynonymRepository) OnChange(fn func(*search.Synonyms)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onChange = append(r.onChange, fn)
+}
+
+// Reload re-reads the dictionary file; an invalid file leaves the current synonyms in place
+func (r *SynonymRepository) Reload() error {
+	r.updateMu.Lock()
+	defer r.updateMu.Unlock()
+
+	dictionary := search.Dictionary{Synonyms: [][]string{}, Abbreviations: map[string]string{}}
+
+	file, err := os.ReadFile(r.filePath)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return fmt.Errorf("failed to read synonyms file: %w", err)
+	default:
+		if err := json.Unmarshal(file, &dictionary); err != nil {
+			return fmt.Errorf("failed to unmarshal synonyms: %w", err)
+		}
+	}
+
+	if err := dictionary.Validate(); err != nil {
+		return fmt.Errorf("invalid synonyms file: %w", err)
+	}
+
+	r.replace(search.NewSynonyms(dictionary))
+	return nil
+}
+
+// Save validates dictionary, writes it to the file and applies it
+func (r *SynonymRepository) Save(dictionary search.Dictionary) error {
+	r.updateMu.Lock()
+	defer r.updateMu.Unlock()
+
+	if err := dictionary.Validate(); err != nil {
+		return err
+	}
+	if dictionary.Synonyms == nil {
+		dictionary.Synonyms = [][]string{}
+	}
+	if dictionary.Abbreviations == nil {
+		dictionary.Abbreviations = map[string]string{}
+	}
+
+	data, err := json.MarshalIndent(dictionary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal synonyms: %w", err)
+	}
+
+	// Write a temporary file and rename it so readers never see a partial file
+	tmp, err := os.CreateTemp(filepath.Dir(r.filePath), ".synonyms-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write synonyms file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write synonyms file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write synonyms file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), r.filePath); err != nil {
+		return fmt.Errorf("failed to write synonyms file: %w", err)
+	}
+
+	r.replace(search.NewSynonyms(dictionary))
+	return nil
+}
+
+// replace swaps in new synonyms and notifies change listeners
+func (r *SynonymRepository) replace(synonyms *search.Synonyms) {
+	r.mu.Lock()
+	r.synonyms = synonyms
+	listeners := r.onChange
+	r.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(synonyms)
+	}
+}
@@ -4,33 +4,45 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"housing-api/internal/models"
 	"housing-api/internal/utils"
 )
 
-// UserRepository handles user data operations
+// UserRepository handles user data operations. Users are held in memory and, when the repository
+// has a file, changes are written to it by Flush.
 type UserRepository struct {
+	mu       sync.RWMutex
 	users    []models.User
 	filePath string
+
+	// dirty marks changes not yet flushed; saveMu serialises writes of the file
+	dirty  bool
+	saveMu sync.Mutex
+}
+
+// storedUser is a user as saved in the users file; User itself never serializes its password hash
+type storedUser struct {
+	models.User
+	PasswordHash string `json:"password_hash"`
 }
 
-// NewUserRepository creates a new user repository
-func NewUserRepository() (*UserRepository, error) {
-	filePath := utils.GetDataFilePath("users.json")
+// NewUserRepository loads the users saved at filePath; a missing file means no users yet, and an
+// empty path keeps users in memory only
+func NewUserRepository(filePath string) (*UserRepository, error) {
 	repo := &UserRepository{
 		filePath: filePath,
 		users:    []models.User{},
 	}
 
-	// Load existing users if file exists, or create demo user
-	if err := repo.loadUsers(); err != nil {
-		// If file doesn't exist, create it with demo user
-		if err := repo.createDemoUser(); err != nil {
-			return nil, fmt.Errorf("failed to create demo user: %w", err)
+	if filePath != "" {
+		if err := repo.loadUsers(); err != nil {
+			return nil, err
 		}
 	}
 
@@ -39,79 +51,117 @@ func NewUserRepository() (*UserRepository, error) {
 
 // loadUsers loads users from JSON file
 func (r *UserRepository) loadUsers() error {
-	// Check if file exists
-	if _, err := os.Stat(r.filePath); os.IsNotExist(err) {
-		return fmt.Errorf("users file does not exist")
-	}
-
 	file, err := os.ReadFile(r.filePath)
-	if err != nil {
-		return fmt.Errorf("failed to read users file: %w", err)
-	}
-
-	if len(file) == 0 {
-		r.users = []models.User{}
+	switch {
+	case os.IsNotExist(err):
 		return nil
+	case err != nil:
+		return fmt.Errorf("failed to read users file: %w", err)
 	}
 
-	if err := json.Unmarshal(file, &r.users); err != nil {
-		return fmt.Errorf("failed to unmarshal users: %w", err)
+	users, err := decodeUsers(file)
+	if err != nil {
+		return err
 	}
 
+	r.mu.Lock()
+	r.users = users
+	r.mu.Unlock()
 	return nil
 }
 
-// saveUsers saves users to JSON file
-func (r *UserRepository) saveUsers() error {
-	// Create data directory if it doesn't exist
-	if err := os.MkdirAll("data", 0755); err != nil {
-		return fmt.Errorf("failed to create data directory: %w", err)
+// decodeUsers parses users as saved by encodeUsers; an empty file holds no users
+func decodeUsers(data []byte) ([]models.User, error) {
+	if len(data) == 0 {
+		return []models.User{}, nil
 	}
 
-	data, err := json.MarshalIndent(r.users, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal users: %w", err)
+	var stored []storedUser
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal users: %w", err)
+	}
+	users := make([]models.User, len(stored))
+	for i, user := range stored {
+		users[i] = user.User
+		users[i].Password = user.PasswordHash
 	}
+	return users, nil
+}
 
-	if err := os.WriteFile(r.filePath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write users file: %w", err)
+// encodeUsers serializes users with their password hashes
+func encodeUsers(users []models.User) ([]byte, error) {
+	stored := make([]storedUser, len(users))
+	for i, user := range users {
+		stored[i] = storedUser{User: user, PasswordHash: user.Password}
 	}
 
-	return nil
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal users: %w", err)
+	}
+	return append(data, '\n'), nil
 }
 
-// createDemoUser creates a demo user for testing
-func (r *UserRepository) createDemoUser() error {
-	// Get demo credentials from environment or use defaults
-	demoEmail := os.Getenv("DEMO_USER_EMAIL")
-	if demoEmail == "" {
-		demoEmail = "demo@worksquare.com"
+// Flush writes unsaved changes to the users file, via a temporary file so readers never see a
+// partial one. It does nothing without changes or a file.
+func (r *UserRepository) Flush() (err error) {
+	if r.filePath == "" {
+		return nil
 	}
 
-	demoPassword := os.Getenv("DEMO_USER_PASSWORD")
-	if demoPassword == "" {
-		demoPassword = "demo123456"
+	r.saveMu.Lock()
+	defer r.saveMu.Unlock()
+
+	r.mu.Lock()
+	if !r.dirty {
+		r.mu.Unlock()
+		return nil
+	}
+	data, err := encodeUsers(r.users)
+	r.dirty = err != nil
+	r.mu.Unlock()
+	if err != nil {
+		return err
 	}
 
-	hashedPassword, err := utils.HashPassword(demoPassword)
+	// A failed write leaves the changes unsaved for the next attempt
+	defer func() {
+		if err != nil {
+			r.markDirty()
+		}
+	}()
+
+	tmp, err := os.CreateTemp(filepath.Dir(r.filePath), ".users-*.json")
 	if err != nil {
-		return fmt.Errorf("failed to hash demo password: %w", err)
+		return fmt.Errorf("failed to write users file: %w", err)
 	}
+	defer os.Remove(tmp.Name())
 
-	demoUser := models.User{
-		ID:        1,
-		Email:     demoEmail,
-		Password:  hashedPassword,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write users file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write users file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), r.filePath); err != nil {
+		return fmt.Errorf("failed to write users file: %w", err)
 	}
+	return nil
+}
 
-	r.users = []models.User{demoUser}
-	return r.saveUsers()
+// markDirty records a change for the next Flush
+func (r *UserRepository) markDirty() {
+	r.mu.Lock()
+	r.dirty = true
+	r.mu.Unlock()
 }
 
 // GetAll returns all users (excluding passwords)
 func (r *UserRepository) GetAll() ([]models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	// Return copy without passwords
 	var users []models.User
 	for _, user := range r.users {
@@ -124,6 +174,13 @@ func (r *UserRepository) GetAll() ([]models.User, error) {
 
 // GetByID returns a user by ID
 func (r *UserRepository) GetByID(id int) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.findByID(id)
+}
+
+// findByID returns a copy of the user with id; r.mu must be held
+func (r *UserRepository) findByID(id int) (*models.User, error) {
 	for _, user := range r.users {
 		if user.ID == id {
 			return &user, nil
@@ -134,6 +191,13 @@ func (r *UserRepository) GetByID(id int) (*models.User, error) {
 
 // GetByEmail returns a user by email
 func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.findByEmail(email)
+}
+
+// findByEmail returns a copy of the user with email; r.mu must be held
+func (r *UserRepository) findByEmail(email string) (*models.User, error) {
 	for _, user := range r.users {
 		if strings.EqualFold(user.Email, email) {
 			return &user, nil
@@ -144,8 +208,11 @@ func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
 
 // Create creates a new user
 func (r *UserRepository) Create(user models.User) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	// Check if user with email already exists
-	if _, err := r.GetByEmail(user.Email); err == nil {
+	if _, err := r.findByEmail(user.Email); err == nil {
 		return nil, fmt.Errorf("user with email %s already exists", user.Email)
 	}
 
@@ -156,17 +223,16 @@ func (r *UserRepository) Create(user models.User) (*models.User, error) {
 
 	// Add user to slice
 	r.users = append(r.users, user)
-
-	// Save to file
-	if err := r.saveUsers(); err != nil {
-		return nil, fmt.Errorf("failed to save user: %w", err)
-	}
+	r.dirty = true
 
 	return &user, nil
 }
 
 // Update updates an existing user
 func (r *UserRepository) Update(id int, updates models.User) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	for i, user := range r.users {
 		if user.ID == id {
 			// Preserve certain fields
@@ -181,13 +247,10 @@ func (r *UserRepository) Update(id int, updates models.User) (*models.User, erro
 
 			// Update the user
 			r.users[i] = updates
+			r.dirty = true
 
-			// Save to file
-			if err := r.saveUsers(); err != nil {
-				return nil, fmt.Errorf("failed to update user: %w", err)
-			}
-
-			return &r.users[i], nil
+			updated := r.users[i]
+			return &updated, nil
 		}
 	}
 	return nil, fmt.Errorf("user with ID %d not found", id)
@@ -195,16 +258,14 @@ func (r *UserRepository) Update(id int, updates models.User) (*models.User, erro
 
 // Delete deletes a user by ID
 func (r *UserRepository) Delete(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	for i, user := range r.users {
 		if user.ID == id {
-			// Remove user from slice
-			r.users = append(r.users[:i], r.users[i+1:]...)
-
-			// Save to file
-			if err := r.saveUsers(); err != nil {
-				return fmt.Errorf("failed to delete user: %w", err)
-			}
-
+			// Remove user from a copy of the slice, as readers may hold the old one
+			r.users = append(r.users[:i:i], r.users[i+1:]...)
+			r.dirty = true
 			return nil
 		}
 	}
@@ -240,14 +301,18 @@ func (r *UserRepository) UpdatePassword(id int, newPassword string) error {
 
 // GetUserCount returns the total number of users
 func (r *UserRepository) GetUserCount() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	return len(r.users)
 }
 
 // GetRecentUsers returns recently registered users
 func (r *UserRepository) GetRecentUsers(limit int) ([]models.User, error) {
 	// Sort users by creation date (most recent first)
+	r.mu.RLock()
 	sortedUsers := make([]models.User, len(r.users))
 	copy(sortedUsers, r.users)
+	r.mu.RUnlock()
 
 	sort.Slice(sortedUsers, func(i, j int) bool {
 		return sortedUsers[i].CreatedAt.After(sortedUsers[j].CreatedAt)
@@ -274,6 +339,9 @@ func (r *UserRepository) SearchUsers(query string) ([]models.User, error) {
 		return r.GetAll()
 	}
 
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	var results []models.User
 	query = strings.ToLower(query)
 
@@ -316,6 +384,9 @@ func (r *UserRepository) UpdateLastLogin(id int) error {
 
 // GetUsersByDateRange returns users created within a date range
 func (r *UserRepository) GetUsersByDateRange(start, end time.Time) ([]models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	var results []models.User
 
 	for _, user := range r.users {
@@ -331,7 +402,9 @@ func (r *UserRepository) GetUsersByDateRange(start, end time.Time) ([]models.Use
 
 // Backup creates a backup of the users data
 func (r *UserRepository) Backup(backupPath string) error {
-	data, err := json.MarshalIndent(r.users, "", "  ")
+	r.mu.RLock()
+	data, err := encodeUsers(r.users)
+	r.mu.RUnlock()
 	if err != nil {
 		return fmt.Errorf("failed to marshal users for backup: %w", err)
 	}
@@ -346,16 +419,19 @@ func (r *UserRepository) Restore(backupPath string) error {
 		return fmt.Errorf("failed to read backup file: %w", err)
 	}
 
-	var users []models.User
-	if err := json.Unmarshal(file, &users); err != nil {
+	users, err := decodeUsers(file)
+	if err != nil {
 		return fmt.Errorf("failed to unmarshal backup data: %w", err)
 	}
 
+	r.mu.Lock()
 	r.users = users
-	return r.saveUsers()
+	r.dirty = true
+	r.mu.Unlock()
+	return nil
 }
 
-// getNextID generates the next available user ID
+// getNextID generates the next available user ID; r.mu must be held
 func (r *UserRepository) getNextID() int {
 	maxID := 0
 	for _, user := range r.users {
@@ -368,8 +444,11 @@ func (r *UserRepository) getNextID() int {
 
 // CleanupOldUsers removes users older than specified duration (for maintenance)
 func (r *UserRepository) CleanupOldUsers(maxAge time.Duration) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	cutoffTime := time.Now().Add(-maxAge)
-	var keptUsers []models.User
+	keptUsers := []models.User{}
 	removedCount := 0
 
 	for _, user := range r.users {
@@ -381,9 +460,7 @@ func (r *UserRepository) CleanupOldUsers(maxAge time.Duration) (int, error) {
 	}
 
 	r.users = keptUsers
-	if err := r.saveUsers(); err != nil {
-		return 0, fmt.Errorf("failed to save after cleanup: %w", err)
-	}
+	r.dirty = r.dirty || removedCount > 0
 
 	return removedCount, nil
-}
\ No newline at end of file
+}
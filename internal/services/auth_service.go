@@ -1,29 +1,35 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"strings"
-	"time"
 
 	"housing-api/internal/config"
 	"housing-api/internal/models"
+	"housing-api/internal/repositories"
 	"housing-api/internal/utils"
 	"housing-api/pkg/jwt"
+	"housing-api/pkg/logger"
+	"housing-api/pkg/shutdown"
+	"housing-api/pkg/tracing"
 )
 
 // AuthService handles authentication business logic
 type AuthService struct {
 	config *config.Config
-	users  []models.User // In-memory user store for demo
+	users  *repositories.UserRepository
 }
 
-func NewAuthService(cfg *config.Config) *AuthService {
+func NewAuthService(cfg *config.Config, users *repositories.UserRepository) *AuthService {
 	service := &AuthService{
 		config: cfg,
-		users:  []models.User{},
+		users:  users,
 	}
 
-	service.createDemoUser()
+	if !users.EmailExists(cfg.DemoUserEmail) {
+		service.createDemoUser()
+	}
 
 	return service
 }
@@ -34,21 +40,19 @@ func (s *AuthService) createDemoUser() {
 		return
 	}
 
-	demoUser := models.User{
-		ID:        1,
-		Email:     s.config.DemoUserEmail,
-		Password:  hashedPassword,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	s.users = append(s.users, demoUser)
+	s.users.Create(models.User{
+		Email:    s.config.DemoUserEmail,
+		Password: hashedPassword,
+	})
 }
 
 // Login authenticates a user and returns JWT tokens
-func (s *AuthService) Login(req models.LoginRequest) (*models.AuthResponse, error) {
+func (s *AuthService) Login(ctx context.Context, req models.LoginRequest) (*models.AuthResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Login")
+	defer span.End()
+
 	email := strings.ToLower(strings.TrimSpace(req.Email))
-	
+
 	// Find user by email
 	user := s.findUserByEmail(email)
 	if user == nil {
@@ -56,7 +60,7 @@ func (s *AuthService) Login(req models.LoginRequest) (*models.AuthResponse, erro
 	}
 
 	// Verify password
-	if !utils.CheckPasswordHash(req.Password, user.Password) {
+	if !s.checkPassword(ctx, req.Password, user.Password) {
 		return nil, fmt.Errorf("invalid credentials")
 	}
 
@@ -80,35 +84,42 @@ func (s *AuthService) Login(req models.LoginRequest) (*models.AuthResponse, erro
 }
 
 // Register creates a new user account
-func (s *AuthService) Register(req models.RegisterRequest) (*models.AuthResponse, error) {
+func (s *AuthService) Register(ctx context.Context, req models.RegisterRequest) (*models.AuthResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Register")
+	defer span.End()
+
 	email := strings.ToLower(strings.TrimSpace(req.Email))
-	
+
 	// Check if email is empty
 	if email == "" {
 		return nil, fmt.Errorf("email must not be empty")
 	}
-	
+
 	// Check if user already exists
 	if s.findUserByEmail(email) != nil {
 		return nil, fmt.Errorf("user with email %s already exists", req.Email)
 	}
 
 	// Hash password
-	hashedPassword, err := utils.HashPassword(req.Password)
+	hashedPassword, err := s.hashPassword(ctx, req.Password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	// Create new user
-	newUser := models.User{
-		ID:        len(s.users) + 1,
-		Email:     email,
-		Password:  hashedPassword,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+	// Create new user; the repository checks again as the same email may have registered while hashing
+	newUser, err := s.users.Create(models.User{
+		Email:    email,
+		Password: hashedPassword,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("user with email %s already exists", req.Email)
 	}
 
-	s.users = append(s.users, newUser)
+	shutdown.Go(func() {
+		if err := s.users.Flush(); err != nil {
+			logger.ErrorContext(ctx, "Failed to save users", "error", err.Error())
+		}
+	})
 
 	// Generate JWT tokens
 	accessToken, err := jwt.GenerateToken(newUser.ID, newUser.Email, s.config.JWTSecret, s.config.JWTExpiresIn)
@@ -177,22 +188,40 @@ func (s *AuthService) GetUserByID(id int) (*models.User, error) {
 	return user, nil
 }
 
+// hashPassword hashes a password inside its own span, as bcrypt dominates request latency
+func (s *AuthService) hashPassword(ctx context.Context, password string) (string, error) {
+	_, span := tracing.Start(ctx, "utils.HashPassword")
+	defer span.End()
+
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		span.RecordError(err)
+	}
+	return hashedPassword, err
+}
+
+// checkPassword verifies a password against its hash inside its own span
+func (s *AuthService) checkPassword(ctx context.Context, password, hash string) bool {
+	_, span := tracing.Start(ctx, "utils.CheckPasswordHash")
+	defer span.End()
+
+	return utils.CheckPasswordHash(password, hash)
+}
+
 // findUserByEmail finds user by email
 func (s *AuthService) findUserByEmail(email string) *models.User {
-	for _, user := range s.users {
-		if user.Email == email {
-			return &user
-		}
+	user, err := s.users.GetByEmail(email)
+	if err != nil {
+		return nil
 	}
-	return nil
+	return user
 }
 
 // findUserByID finds user by ID
 func (s *AuthService) findUserByID(id int) *models.User {
-	for _, user := range s.users {
-		if user.ID == id {
-			return &user
-		}
+	user, err := s.users.GetByID(id)
+	if err != nil {
+		return nil
 	}
-	return nil
-}
\ No newline at end of file
+	return user
+}
@@ -1,39 +1,263 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	"housing-api/internal/config"
 	"housing-api/internal/models"
 	"housing-api/internal/repositories"
+	"housing-api/internal/utils"
+	"housing-api/pkg/cache"
+	"housing-api/pkg/logger"
 	"housing-api/pkg/pagination"
+	"housing-api/pkg/search"
+	"housing-api/pkg/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // ListingService handles business logic for listings
 type ListingService struct {
 	repo *repositories.ListingRepository
+
+	// cache holds computed results (stats, filter metadata, paginated pages); it is purged
+	// whenever the listing data changes. Cached values are shared and must not be modified.
+	cache *cache.Cache[any]
+
+	// cursors signs and verifies keyset pagination cursors
+	cursors *pagination.CursorCodec
+
+	// synonyms holds the search synonym dictionary applied by repo
+	synonyms *repositories.SynonymRepository
+
+	// gazetteer holds the places repo geocodes listings without coordinates from
+	gazetteer *repositories.GazetteerRepository
+
+	// priceBuckets are the ascending upper bounds of the price facet buckets
+	priceBuckets []int
 }
 
 // NewListingService creates a new listing service
-func NewListingService() (*ListingService, error) {
+func NewListingService(cfg *config.Config) (*ListingService, error) {
 	repo, err := repositories.NewListingRepository()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create listing repository: %w", err)
 	}
 
-	return &ListingService{
-		repo: repo,
-	}, nil
+	synonymsFile := cfg.SearchSynonymsFile
+	if synonymsFile == "" {
+		synonymsFile = utils.GetDataFilePath("synonyms.json")
+	}
+	synonyms, err := repositories.NewSynonymRepository(synonymsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create synonym repository: %w", err)
+	}
+
+	gazetteerFile := cfg.GeocoderGazetteerFile
+	if gazetteerFile == "" {
+		gazetteerFile = utils.GetDataFilePath("gazetteer.json")
+	}
+	gazetteer, err := repositories.NewGazetteerRepository(gazetteerFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gazetteer repository: %w", err)
+	}
+
+	cursorSecret := cfg.CursorSecret
+	if cursorSecret == "" {
+		cursorSecret = cfg.JWTSecret
+	}
+
+	s := &ListingService{
+		repo:      repo,
+		cache:     cache.New[any]("listings", cfg.CacheMaxEntries, cfg.CacheTTL),
+		cursors:   pagination.NewCursorCodec(cursorSecret),
+		synonyms:  synonyms,
+		gazetteer: gazetteer,
+
+		priceBuckets: cfg.FacetPriceBuckets,
+	}
+	repo.OnChange(s.cache.Purge)
+	repo.SetSynonyms(synonyms.Get())
+	synonyms.OnChange(repo.SetSynonyms)
+	repo.SetGazetteer(gazetteer.Get())
+	gazetteer.OnChange(repo.SetGazetteer)
+	s.logUnresolved(context.Background())
+
+	return s, nil
 }
 
-// GetListings returns paginated listings with optional filtering
-func (s *ListingService) GetListings(filter models.ListingFilter, paginationQuery models.PaginationQuery) (*models.PaginatedResponse, error) {
+// ReloadListings re-reads listing data from disk, invalidating cached results
+func (s *ListingService) ReloadListings(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "ListingService.ReloadListings")
+	defer span.End()
+
+	if err := s.repo.ReloadListings(); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to reload listings: %w", err)
+	}
+	s.logUnresolved(ctx)
+	return nil
+}
+
+// GetSynonyms returns the search synonym dictionary in effect
+func (s *ListingService) GetSynonyms() search.Dictionary {
+	return s.synonyms.Get().Dictionary()
+}
+
+// UpdateSynonyms validates and saves a new synonym dictionary, applying it immediately. Invalid
+// dictionaries are reported as a ValidationError.
+func (s *ListingService) UpdateSynonyms(ctx context.Context, dictionary search.Dictionary) error {
+	_, span := tracing.Start(ctx, "ListingService.UpdateSynonyms")
+	defer span.End()
+
+	if err := dictionary.Validate(); err != nil {
+		return models.ValidationError{Field: "synonyms", Message: err.Error()}
+	}
+	if err := s.synonyms.Save(dictionary); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to save synonyms: %w", err)
+	}
+	return nil
+}
+
+// ReloadSynonyms re-reads the synonym dictionary from disk
+func (s *ListingService) ReloadSynonyms(ctx context.Context) error {
+	_, span := tracing.Start(ctx, "ListingService.ReloadSynonyms")
+	defer span.End()
+
+	if err := s.synonyms.Reload(); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to reload synonyms: %w", err)
+	}
+	return nil
+}
+
+// GetGeocodingReport summarises how the loaded listings got their coordinates, listing those the
+// gazetteer could not locate
+func (s *ListingService) GetGeocodingReport() models.GeocodingReport {
+	return s.repo.GeocodingReport()
+}
+
+// ReloadGazetteer re-reads the gazetteer from disk and geocodes the listings again
+func (s *ListingService) ReloadGazetteer(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "ListingService.ReloadGazetteer")
+	defer span.End()
+
+	if err := s.gazetteer.Reload(); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to reload gazetteer: %w", err)
+	}
+	s.logUnresolved(ctx)
+	return nil
+}
+
+// logUnresolved warns about listings left without coordinates after geocoding, so gaps in the
+// gazetteer show up whenever listings or the gazetteer are loaded; reloads log with their request
+func (s *ListingService) logUnresolved(ctx context.Context) {
+	report := s.repo.GeocodingReport()
+	if len(report.Unresolved) == 0 {
+		return
+	}
+
+	locations := make([]string, len(report.Unresolved))
+	for i, listing := range report.Unresolved {
+		locations[i] = fmt.Sprintf("%d: %s", listing.ID, listing.Location)
+	}
+	logger.WarnContext(ctx, "Listings could not be geocoded",
+		"count", len(report.Unresolved),
+		"listings", strings.Join(locations, "; "),
+	)
+}
+
+// CacheStats reports hit and miss counts for the service's result cache
+func (s *ListingService) CacheStats() []cache.Stats {
+	return []cache.Stats{s.cache.Stats()}
+}
+
+// GetListings returns paginated listings with optional filtering. A cursor from a previous page's
+// next_cursor continues after that page (keyset pagination); otherwise page/limit apply.
+// Facet counts named in filter.Facets are returned alongside the page.
+func (s *ListingService) GetListings(ctx context.Context, filter models.ListingFilter, paginationQuery models.PaginationQuery) (*models.PaginatedResponse, error) {
+	ctx, span := tracing.Start(ctx, "ListingService.GetListings")
+	defer span.End()
+
 	paginationQuery.SetDefaults()
+	filter.Normalize()
+	sorting, err := models.ParseSort(paginationQuery.Sort, filter.Query != "")
+	if err != nil {
+		return nil, err
+	}
+	paginationQuery.Sort = sorting.String()
+	facets, err := models.ParseFacets(filter.Facets)
+	if err != nil {
+		return nil, err
+	}
+	filter.Facets = strings.Join(facets, ",")
+	if filter.Filter != "" {
+		if _, err := models.ParseFilterExpression(filter.Filter); err != nil {
+			return nil, err
+		}
+	}
+	spatial, err := filter.ParseGeo()
+	if err != nil {
+		return nil, err
+	}
+	if err := sorting.CheckNear(spatial.Near != nil); err != nil {
+		return nil, err
+	}
 
-	// Get paginated listings
-	listings, total, err := s.repo.GetPaginated(filter, paginationQuery.Page, paginationQuery.Limit)
+	span.SetAttributes(
+		attribute.Int("pagination.page", paginationQuery.Page),
+		attribute.Int("pagination.limit", paginationQuery.Limit),
+		attribute.Bool("pagination.cursor", paginationQuery.Cursor != ""),
+		attribute.String("pagination.sort", paginationQuery.Sort),
+	)
+
+	key, cacheable := listingsCacheKey(filter, paginationQuery)
+	result, err := s.cached(key, cacheable, func() (any, error) {
+		return s.getListings(ctx, filter, sorting, paginationQuery)
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return result.(*models.PaginatedResponse), nil
+}
+
+// getListings computes a page of listings without the cache
+func (s *ListingService) getListings(ctx context.Context, filter models.ListingFilter, sorting models.Sort, paginationQuery models.PaginationQuery) (*models.PaginatedResponse, error) {
+	span := trace.SpanFromContext(ctx)
+
+	// Get paginated listings, seeking past the cursor position when one is given
+	var (
+		listings []models.Listing
+		total    int64
+		next     *models.SortPosition
+		err      error
+	)
+	if paginationQuery.Cursor != "" {
+		cursor, decodeErr := s.cursors.Decode(paginationQuery.Cursor, sorting.String())
+		if decodeErr != nil {
+			return nil, decodeErr
+		}
+		listings, next, err = s.repo.GetAfter(ctx, filter, sorting, cursor.SortPosition, paginationQuery.Limit)
+		if err == nil {
+			total, err = s.countListings(ctx, filter, sorting)
+		}
+	} else {
+		listings, total, next, err = s.repo.GetPaginated(ctx, filter, sorting, paginationQuery.Page, paginationQuery.Limit)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get listings: %w", err)
 	}
+	span.SetAttributes(attribute.Int64("listings.total", total))
 
 	// Convert to interface slice
 	items := make([]interface{}, len(listings))
@@ -43,11 +267,83 @@ func (s *ListingService) GetListings(filter models.ListingFilter, paginationQuer
 
 	// Calculate pagination metadata
 	meta := pagination.CalculateMetadata(paginationQuery.Page, paginationQuery.Limit, total)
+	if paginationQuery.Cursor != "" {
+		// A cursor page has no page number
+		meta.Page = 0
+	}
+	if next != nil {
+		meta.NextCursor = s.cursors.Encode(pagination.Cursor{Sort: sorting.String(), SortPosition: *next})
+	}
 
-	return &models.PaginatedResponse{
+	result := &models.PaginatedResponse{
 		Items: items,
 		Meta:  meta,
-	}, nil
+	}
+	if filter.Facets != "" {
+		result.Facets = s.repo.GetFacets(filter, strings.Split(filter.Facets, ","), s.priceBuckets)
+	}
+	if total == 0 && filter.Query != "" {
+		result.DidYouMean = s.repo.SuggestQuery(filter.Query)
+	}
+
+	return result, nil
+}
+
+// OffsetPage is a page of listings found by offset, as the OData endpoint pages them
+type OffsetPage struct {
+	Items []models.Listing
+	Total int64
+}
+
+// QueryListings returns up to limit listings matching filter in sorting order after skipping the
+// first offset of them, with the total number of matches. Unlike GetListings the offset needn't
+// be a multiple of the limit.
+func (s *ListingService) QueryListings(ctx context.Context, filter models.ListingFilter, sorting models.Sort, offset, limit int) (*OffsetPage, error) {
+	ctx, span := tracing.Start(ctx, "ListingService.QueryListings")
+	defer span.End()
+
+	filter.Normalize()
+	filter.Facets = ""
+	if filter.Filter != "" {
+		if _, err := models.ParseFilterExpression(filter.Filter); err != nil {
+			return nil, err
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("pagination.offset", offset),
+		attribute.Int("pagination.limit", limit),
+		attribute.String("pagination.sort", sorting.String()),
+	)
+
+	// Page 0 keeps these entries apart from GetListings pages, which start at 1
+	key, cacheable := listingsCacheKey(filter, models.PaginationQuery{Limit: limit, Sort: sorting.String()})
+	result, err := s.cached(fmt.Sprintf("%s:offset=%d", key, offset), cacheable, func() (any, error) {
+		listings, total, _, err := s.repo.GetOffset(ctx, filter, sorting, offset, limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get listings: %w", err)
+		}
+		return &OffsetPage{Items: listings, Total: total}, nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return result.(*OffsetPage), nil
+}
+
+// CheckDataLoaded reports whether listing data is loaded, for readiness probes
+func (s *ListingService) CheckDataLoaded(ctx context.Context) error {
+	if s.repo.GetTotalCount() == 0 {
+		return fmt.Errorf("no listings loaded")
+	}
+	return nil
+}
+
+// LastModified returns when the listing data last changed, for conditional requests
+func (s *ListingService) LastModified() time.Time {
+	return s.repo.LastModified()
 }
 
 // GetListingByID returns a single listing by ID
@@ -60,30 +356,139 @@ func (s *ListingService) GetListingByID(id int) (*models.Listing, error) {
 	return listing, nil
 }
 
+// cached returns the result cached under key, loading and caching it on a miss. Results that
+// aren't cacheable are loaded every time.
+func (s *ListingService) cached(key string, cacheable bool, load func() (any, error)) (any, error) {
+	if !cacheable {
+		return load()
+	}
+	return s.cache.GetOrLoad(key, load)
+}
+
+// countListings returns the number of listings matching filter. Counts are cached per filter, so
+// following a cursor through the results counts the matches once rather than on every page.
+func (s *ListingService) countListings(ctx context.Context, filter models.ListingFilter, sorting models.Sort) (int64, error) {
+	key, cacheable := listingsCacheKey(filter, models.PaginationQuery{})
+	total, err := s.cached("total:"+key, cacheable, func() (any, error) {
+		return s.repo.Count(ctx, filter, sorting), nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total.(int64), nil
+}
+
+// listingsCacheKey identifies a page of results by its normalized filter and pagination. A filter
+// that can't be serialized (a NaN bound, say) has no key and must not be cached: it would
+// otherwise share an entry with every other such filter.
+func listingsCacheKey(filter models.ListingFilter, paginationQuery models.PaginationQuery) (string, bool) {
+	// Text filters match case-insensitively and in any order, so equivalent queries share an entry
+	for _, values := range []*[]string{
+		&filter.Location, &filter.City, &filter.PropertyType,
+		&filter.ExcludeLocation, &filter.ExcludeCity, &filter.ExcludePropertyType,
+	} {
+		*values = normalizedValues(*values)
+	}
+	filter.Query = strings.ToLower(strings.TrimSpace(filter.Query))
+
+	// A parsed expression isn't serialized with the filter; its canonical form stands in for it
+	var expression string
+	if filter.Expression != nil {
+		expression = filter.Expression.String()
+	}
+
+	key, err := json.Marshal(struct {
+		Filter     models.ListingFilter `json:"filter"`
+		Expression string               `json:"expression,omitempty"`
+		Page       int                  `json:"page"`
+		Limit      int                  `json:"limit"`
+		Cursor     string               `json:"cursor,omitempty"`
+		Sort       string               `json:"sort"`
+	}{filter, expression, paginationQuery.Page, paginationQuery.Limit, paginationQuery.Cursor, paginationQuery.Sort})
+	if err != nil {
+		return "", false
+	}
+
+	return "listings:" + string(key), true
+}
+
+// normalizedValues lowercases and sorts filter values without modifying them in place
+func normalizedValues(values []string) []string {
+	normalized := make([]string, len(values))
+	for i, value := range values {
+		normalized[i] = strings.ToLower(value)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
 // GetFiltersMetadata returns metadata for filtering (unique locations, property types)
 func (s *ListingService) GetFiltersMetadata() (map[string]interface{}, error) {
+	metadata, err := s.cache.GetOrLoad("filters", func() (any, error) {
+		return s.getFiltersMetadata()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return metadata.(map[string]interface{}), nil
+}
+
+// getFiltersMetadata computes filter metadata without the cache
+func (s *ListingService) getFiltersMetadata() (map[string]interface{}, error) {
 	locations := s.repo.GetUniqueLocations()
 	propertyTypes := s.repo.GetUniquePropertyTypes()
 
+	expressionFields := make(map[string]string, len(models.ListingFilterFields))
+	for name, fieldType := range models.ListingFilterFields {
+		expressionFields[name] = fieldType.String()
+	}
+
 	metadata := map[string]interface{}{
 		"locations":      locations,
 		"property_types": propertyTypes,
 		"filters": map[string]interface{}{
+			"q": map[string]interface{}{
+				"type":        "string",
+				"description": "Full-text search over title, location and property type (all terms must match, typos and synonyms such as VI or BQ understood); empty results carry a did_you_mean suggestion",
+				"example":     "duplex lekki",
+			},
 			"location": map[string]interface{}{
 				"type":        "string",
-				"description": "Filter by location (partial match)",
-				"example":     "Lagos",
+				"description": "Filter by location (partial match, tolerates typos and synonyms); several values match any",
+				"multiple":    true,
+				"example":     "Lekki,Ikoyi",
 			},
 			"property_type": map[string]interface{}{
 				"type":        "string",
-				"description": "Filter by property type (exact match)",
+				"description": "Filter by property type (exact match of the type or a synonym, e.g. flat for Apartment); several values match any",
+				"multiple":    true,
 				"options":     propertyTypes,
-				"example":     "House",
+				"example":     "House,Duplex",
 			},
 			"city": map[string]interface{}{
 				"type":        "string",
-				"description": "Filter by city (partial match)",
-				"example":     "Lagos",
+				"description": "Filter by city (partial match, tolerates typos and synonyms); several values match any",
+				"multiple":    true,
+				"example":     "Lagos,Abuja",
+			},
+			"exclude_location": map[string]interface{}{
+				"type":        "string",
+				"description": "Exclude listings whose location matches any of the values",
+				"multiple":    true,
+				"example":     "Ajah",
+			},
+			"exclude_property_type": map[string]interface{}{
+				"type":        "string",
+				"description": "Exclude listings of any of the property types (or their synonyms)",
+				"multiple":    true,
+				"options":     propertyTypes,
+				"example":     "Flat",
+			},
+			"exclude_city": map[string]interface{}{
+				"type":        "string",
+				"description": "Exclude listings in any of the cities",
+				"multiple":    true,
+				"example":     "Abuja",
 			},
 			"min_price": map[string]interface{}{
 				"type":        "integer",
@@ -115,6 +520,74 @@ func (s *ListingService) GetFiltersMetadata() (map[string]interface{}, error) {
 				"description": "Maximum number of bathrooms",
 				"example":     4,
 			},
+			"near": map[string]interface{}{
+				"type":        "string",
+				"description": "A lat,lng point; results carry distance_km from it and can be sorted by distance",
+				"example":     "6.4478,3.4723",
+			},
+			"radius_km": map[string]interface{}{
+				"type":        "number",
+				"description": "Only listings within this many kilometres of near (requires near)",
+				"maximum":     models.MaxRadiusKm,
+				"example":     5,
+			},
+			"min_lat": map[string]interface{}{
+				"type":        "number",
+				"description": "Southern edge of a bounding box",
+				"example":     6.40,
+			},
+			"max_lat": map[string]interface{}{
+				"type":        "number",
+				"description": "Northern edge of a bounding box",
+				"example":     6.48,
+			},
+			"min_lng": map[string]interface{}{
+				"type":        "number",
+				"description": "Western edge of a bounding box",
+				"example":     3.38,
+			},
+			"max_lng": map[string]interface{}{
+				"type":        "number",
+				"description": "Eastern edge of a bounding box",
+				"example":     3.50,
+			},
+		},
+		"filter": map[string]interface{}{
+			"type":        "string",
+			"description": "Filter expression combining field comparisons with AND, OR, NOT and parentheses; ANDed with the other filters",
+			"operators": map[string]string{
+				":":  "equals (case-insensitive for text); = is an alias",
+				"!=": "does not equal",
+				"~":  "text contains",
+				"<":  "less than (numbers)",
+				"<=": "at most (numbers)",
+				">":  "greater than (numbers)",
+				">=": "at least (numbers)",
+			},
+			"fields":  expressionFields,
+			"example": `(city:Lagos OR city:Abuja) AND price<=3000000 AND bedrooms>=3 AND title~"pool"`,
+		},
+		"geo": map[string]interface{}{
+			"description": "near, radius_km and the bounding box filters only match listings with coordinates; near alone just adds distances",
+			"examples":    []string{"near=6.4478,3.4723&radius_km=5&sort=distance", "min_lat=6.40&max_lat=6.48&min_lng=3.38&max_lng=3.50"},
+		},
+		"multiple_values": map[string]interface{}{
+			"description": "Filters marked multiple take comma-separated or repeated values and match listings with any of them",
+			"examples":    []string{"property_type=House,Duplex", "city=Lagos&city=Abuja", "exclude_property_type=Flat"},
+		},
+		"sort": map[string]interface{}{
+			"type":        "string",
+			"description": "Comma-separated sort fields, each optionally prefixed with - for descending order; ties are broken by ascending ID",
+			"fields":      models.SortFields,
+			"default":     models.DefaultSort,
+			"example":     "-price,bedrooms",
+		},
+		"facets": map[string]interface{}{
+			"type":          "string",
+			"description":   "Comma-separated facets (or all) to count over the filtered results; each facet ignores its own filter",
+			"options":       models.FacetNames,
+			"price_buckets": s.priceBuckets,
+			"example":       "property_type,city,price",
 		},
 		"pagination": map[string]interface{}{
 			"page": map[string]interface{}{
@@ -130,23 +603,46 @@ func (s *ListingService) GetFiltersMetadata() (map[string]interface{}, error) {
 				"minimum":     1,
 				"maximum":     100,
 			},
+			"cursor": map[string]interface{}{
+				"type":        "string",
+				"description": "Opaque cursor from a previous response's next_cursor; continues after that page and takes precedence over page",
+			},
 		},
 	}
 
 	return metadata, nil
 }
 
-// SearchListings provides advanced search functionality
-func (s *ListingService) SearchListings(query string, filter models.ListingFilter, paginationQuery models.PaginationQuery) (*models.PaginatedResponse, error) {
-	if query != "" && filter.Location == "" {
-		filter.Location = query
+// SearchListings runs a full-text search over title, location and property type, combined with
+// the filter's other predicates. Results are ranked by relevance unless another sort is requested.
+func (s *ListingService) SearchListings(ctx context.Context, query string, filter models.ListingFilter, paginationQuery models.PaginationQuery) (*models.PaginatedResponse, error) {
+	filter.Query = query
+	if paginationQuery.Sort == "" && query != "" {
+		paginationQuery.Sort = models.SortRelevance
 	}
 
-	return s.GetListings(filter, paginationQuery)
+	return s.GetListings(ctx, filter, paginationQuery)
+}
+
+// GetSuggestions returns autocomplete suggestions (areas, cities, property types and titles)
+// for a partial query, each with the number of listings behind it
+func (s *ListingService) GetSuggestions(query string, limit int) []search.Completion {
+	return s.repo.Complete(query, limit)
 }
 
 // GetListingStats returns statistics about listings
 func (s *ListingService) GetListingStats() (map[string]interface{}, error) {
+	stats, err := s.cache.GetOrLoad("stats", func() (any, error) {
+		return s.getListingStats()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats.(map[string]interface{}), nil
+}
+
+// getListingStats computes listing statistics without the cache
+func (s *ListingService) getListingStats() (map[string]interface{}, error) {
 	allListings, err := s.repo.GetAll(models.ListingFilter{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get listings for stats: %w", err)
@@ -156,11 +652,11 @@ func (s *ListingService) GetListingStats() (map[string]interface{}, error) {
 	propertyTypes := make(map[string]int)
 	cities := make(map[string]int)
 	priceRanges := map[string]int{
-		"under_1m":    0,
-		"1m_to_2m":    0,
-		"2m_to_3m":    0,
-		"3m_to_5m":    0,
-		"above_5m":    0,
+		"under_1m": 0,
+		"1m_to_2m": 0,
+		"2m_to_3m": 0,
+		"3m_to_5m": 0,
+		"above_5m": 0,
 	}
 
 	var totalPrice, minPrice, maxPrice float64
@@ -212,10 +708,10 @@ func (s *ListingService) GetListingStats() (map[string]interface{}, error) {
 	}
 
 	stats := map[string]interface{}{
-		"total_listings":  totalListings,
-		"property_types":  propertyTypes,
-		"cities":          cities,
-		"price_ranges":    priceRanges,
+		"total_listings": totalListings,
+		"property_types": propertyTypes,
+		"cities":         cities,
+		"price_ranges":   priceRanges,
 		"price_stats": map[string]interface{}{
 			"average": avgPrice,
 			"minimum": minPrice,
@@ -224,4 +720,4 @@ func (s *ListingService) GetListingStats() (map[string]interface{}, error) {
 	}
 
 	return stats, nil
-}
\ No newline at end of file
+}
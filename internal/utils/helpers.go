@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -10,7 +11,7 @@ import (
 func GetProjectRoot() string {
 	_, filename, _, _ := runtime.Caller(0)
 	dir := filepath.Dir(filename)
-	
+
 	// Go up directories until we find go.mod
 	for {
 		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
@@ -29,4 +30,20 @@ func GetProjectRoot() string {
 func GetDataFilePath(filename string) string {
 	projectRoot := GetProjectRoot()
 	return filepath.Join(projectRoot, "data", filename)
-}
\ No newline at end of file
+}
+
+// CheckDirWritable verifies that files can be created in dir
+func CheckDirWritable(dir string) error {
+	file, err := os.CreateTemp(dir, ".write-check-*")
+	if err != nil {
+		return fmt.Errorf("directory %s is not writable: %w", dir, err)
+	}
+
+	name := file.Name()
+	_ = file.Close()
+	if err := os.Remove(name); err != nil {
+		return fmt.Errorf("failed to remove write check file: %w", err)
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"housing-api/cmd/server"
 	"log"
+	"os"
 )
 
 // @title Worksquare Housing Listings API
@@ -22,7 +23,17 @@ import (
 // @description Type "Bearer" followed by a space and JWT token.
 
 func main() {
-	if err := server.Run(); err != nil {
-		log.Fatal("Failed to start server:", err)
+	args := os.Args[1:]
+
+	// `config print [flags]` shows the effective configuration and exits
+	if len(args) >= 2 && args[0] == "config" && args[1] == "print" {
+		if err := server.PrintConfig(os.Stdout, args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if err := server.Run(args); err != nil {
+		log.Fatal("Failed to start server: ", err)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,193 @@
+package cache
+
+import (
+	"container/list"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"housing-api/pkg/metrics"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Stats reports how effective a cache is
+type Stats struct {
+	Name     string `json:"name"`
+	Hits     uint64 `json:"hits"`
+	Misses   uint64 `json:"misses"`
+	Entries  int    `json:"entries"`
+	Capacity int    `json:"capacity"`
+}
+
+// Cache is a bounded LRU cache whose entries expire after a TTL. Concurrent loads of the same
+// key are collapsed into one, and a Purge discards loads that were already in flight.
+type Cache[V any] struct {
+	name     string
+	capacity int
+	ttl      time.Duration
+
+	mu         sync.Mutex
+	items      map[string]*list.Element
+	order      *list.List // most recently used at the front
+	generation uint64
+
+	group  singleflight.Group
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+type entry[V any] struct {
+	key     string
+	value   V
+	expires time.Time
+}
+
+// New creates a cache holding at most capacity entries for up to ttl each.
+// A capacity of zero or less disables storage; a ttl of zero or less never expires entries.
+func New[V any](name string, capacity int, ttl time.Duration) *Cache[V] {
+	return &Cache[V]{
+		name:     name,
+		capacity: capacity,
+		ttl:      ttl,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the cached value for key, if present and not expired
+func (c *Cache[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	value, ok := c.lookup(key)
+	c.record(ok)
+	return value, ok
+}
+
+// GetOrLoad returns the cached value for key, calling load on a miss. Concurrent callers for
+// the same key share a single load; failed loads are not cached.
+func (c *Cache[V]) GetOrLoad(key string, load func() (V, error)) (V, error) {
+	c.mu.Lock()
+	value, ok := c.lookup(key)
+	generation := c.generation
+	c.mu.Unlock()
+
+	c.record(ok)
+	if ok {
+		return value, nil
+	}
+
+	// Keying the flight by generation keeps callers after a Purge from sharing a stale load
+	flightKey := strconv.FormatUint(generation, 10) + ":" + key
+	result, err, _ := c.group.Do(flightKey, func() (interface{}, error) {
+		loaded, err := load()
+		if err != nil {
+			return loaded, err
+		}
+
+		c.mu.Lock()
+		if c.generation == generation {
+			c.store(key, loaded)
+		}
+		c.mu.Unlock()
+		return loaded, nil
+	})
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+
+	return result.(V), nil
+}
+
+// Set stores value under key, evicting the least recently used entry when full
+func (c *Cache[V]) Set(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.store(key, value)
+}
+
+// Purge removes every entry and invalidates loads in flight
+func (c *Cache[V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[string]*list.Element)
+	c.order.Init()
+	c.generation++
+	metrics.SetCacheEntries(c.name, 0)
+}
+
+// Stats returns the cache's hit and miss counts and current size
+func (c *Cache[V]) Stats() Stats {
+	c.mu.Lock()
+	entries := c.order.Len()
+	c.mu.Unlock()
+
+	return Stats{
+		Name:     c.name,
+		Hits:     c.hits.Load(),
+		Misses:   c.misses.Load(),
+		Entries:  entries,
+		Capacity: c.capacity,
+	}
+}
+
+// lookup finds a live entry and marks it recently used; callers must hold mu
+func (c *Cache[V]) lookup(key string) (V, bool) {
+	var zero V
+
+	element, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+
+	e := element.Value.(*entry[V])
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		c.order.Remove(element)
+		delete(c.items, key)
+		metrics.SetCacheEntries(c.name, c.order.Len())
+		return zero, false
+	}
+
+	c.order.MoveToFront(element)
+	return e.value, true
+}
+
+// store inserts or replaces an entry, evicting from the back; callers must hold mu
+func (c *Cache[V]) store(key string, value V) {
+	if c.capacity <= 0 {
+		return
+	}
+
+	var expires time.Time
+	if c.ttl > 0 {
+		expires = time.Now().Add(c.ttl)
+	}
+
+	if element, ok := c.items[key]; ok {
+		element.Value = &entry[V]{key: key, value: value, expires: expires}
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&entry[V]{key: key, value: value, expires: expires})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*entry[V]).key)
+	}
+	metrics.SetCacheEntries(c.name, c.order.Len())
+}
+
+// record counts a lookup as a hit or miss
+func (c *Cache[V]) record(hit bool) {
+	if hit {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	metrics.IncCacheLookup(c.name, hit)
+}
@@ -0,0 +1,140 @@
+package filterexpr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Type is the type of a field or value
+type Type int
+
+const (
+	String Type = iota
+	Number
+)
+
+func (t Type) String() string {
+	if t == Number {
+		return "number"
+	}
+	return "string"
+}
+
+// Schema maps the field names an expression may reference to their types
+type Schema map[string]Type
+
+// Operator is a comparison operator
+type Operator string
+
+const (
+	Equal          Operator = "="
+	NotEqual       Operator = "!="
+	Less           Operator = "<"
+	LessOrEqual    Operator = "<="
+	Greater        Operator = ">"
+	GreaterOrEqual Operator = ">="
+	Contains       Operator = "~"
+)
+
+// supports reports whether op applies to fields of type t: strings compare for (in)equality
+// and containment, numbers for equality and order
+func (op Operator) supports(t Type) bool {
+	switch op {
+	case Equal, NotEqual:
+		return true
+	case Contains:
+		return t == String
+	default:
+		return t == Number
+	}
+}
+
+// Value is a literal or field value
+type Value struct {
+	Type   Type
+	Text   string
+	Number float64
+}
+
+// StringValue returns a string Value
+func StringValue(s string) Value {
+	return Value{Type: String, Text: s}
+}
+
+// NumberValue returns a number Value
+func NumberValue(n float64) Value {
+	return Value{Type: Number, Number: n, Text: strconv.FormatFloat(n, 'f', -1, 64)}
+}
+
+// Node is a node of a parsed filter expression
+type Node interface {
+	// String renders the node in canonical filter syntax, fully parenthesized
+	String() string
+}
+
+// And matches when both operands match
+type And struct {
+	Left, Right Node
+}
+
+// Or matches when either operand matches
+type Or struct {
+	Left, Right Node
+}
+
+// Not matches when its operand doesn't
+type Not struct {
+	Operand Node
+}
+
+// Comparison compares a field with a literal value
+type Comparison struct {
+	Field  string
+	Op     Operator
+	Value  Value
+	Column int
+}
+
+func (n *And) String() string { return "(" + n.Left.String() + " AND " + n.Right.String() + ")" }
+func (n *Or) String() string  { return "(" + n.Left.String() + " OR " + n.Right.String() + ")" }
+func (n *Not) String() string { return "NOT " + n.Operand.String() }
+
+func (n *Comparison) String() string {
+	if n.Value.Type == Number {
+		return n.Field + string(n.Op) + n.Value.Text
+	}
+	return n.Field + string(n.Op) + Quote(n.Value.Text)
+}
+
+// Quote returns s as a filter string literal, escaping only quotes and backslashes, so String
+// output parses back to the same expression
+func Quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// Error is a syntax or type error at a 1-based column of the expression
+type Error struct {
+	Column  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Column, e.Message)
+}
+
+// errorAt returns an Error at the byte offset in input
+func errorAt(input string, offset int, format string, args ...any) *Error {
+	return &Error{Column: column(input, offset), Message: fmt.Sprintf(format, args...)}
+}
+
+// column converts a byte offset in input to a 1-based character column
+func column(input string, offset int) int {
+	return utf8.RuneCountInString(input[:offset]) + 1
+}
+
+// keyword reports whether a bare word is the given keyword, ignoring case
+func keyword(t token, word string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, word)
+}
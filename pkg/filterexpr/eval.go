@@ -0,0 +1,57 @@
+package filterexpr
+
+import "strings"
+
+// Record supplies field values to Eval; ok is false when the record has no value for the field
+type Record interface {
+	FilterValue(field string) (value Value, ok bool)
+}
+
+// Eval reports whether record matches node. A comparison against a missing value is false,
+// whatever the operator, so its negation is true; SQL compiles comparisons to agree.
+func Eval(node Node, record Record) bool {
+	switch n := node.(type) {
+	case *And:
+		return Eval(n.Left, record) && Eval(n.Right, record)
+	case *Or:
+		return Eval(n.Left, record) || Eval(n.Right, record)
+	case *Not:
+		return !Eval(n.Operand, record)
+	case *Comparison:
+		value, ok := record.FilterValue(n.Field)
+		return ok && compare(value, n.Op, n.Value)
+	}
+	return false
+}
+
+// compare applies op to a field value and a literal of the same type
+func compare(value Value, op Operator, literal Value) bool {
+	if literal.Type == Number {
+		a, b := value.Number, literal.Number
+		switch op {
+		case Equal:
+			return a == b
+		case NotEqual:
+			return a != b
+		case Less:
+			return a < b
+		case LessOrEqual:
+			return a <= b
+		case Greater:
+			return a > b
+		case GreaterOrEqual:
+			return a >= b
+		}
+		return false
+	}
+
+	switch op {
+	case Equal:
+		return strings.EqualFold(value.Text, literal.Text)
+	case NotEqual:
+		return !strings.EqualFold(value.Text, literal.Text)
+	case Contains:
+		return strings.Contains(strings.ToLower(value.Text), strings.ToLower(literal.Text))
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package filterexpr
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tokenKind classifies a lexical token
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenLParen
+	tokenRParen
+)
+
+// token is a lexical token and the byte offset it starts at
+type token struct {
+	kind   tokenKind
+	text   string
+	offset int
+}
+
+// operators are the comparison operator spellings, longest first so "<=" wins over "<"; ":" is
+// an alias of "="
+var operators = []string{"<=", ">=", "!=", "=", ":", "<", ">", "~"}
+
+// lex splits input into tokens: parentheses, operators, quoted strings and bare words
+func lex(input string) ([]token, error) {
+	var tokens []token
+
+	for offset := 0; offset < len(input); {
+		r, size := utf8.DecodeRuneInString(input[offset:])
+		switch {
+		case unicode.IsSpace(r):
+			offset += size
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", offset: offset})
+			offset += size
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", offset: offset})
+			offset += size
+		case r == '"':
+			text, end, err := lexString(input, offset)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, offset: offset})
+			offset = end
+		case isWordRune(r):
+			end := offset
+			for end < len(input) {
+				r, size := utf8.DecodeRuneInString(input[end:])
+				if !isWordRune(r) {
+					break
+				}
+				end += size
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: input[offset:end], offset: offset})
+			offset = end
+		default:
+			op, ok := lexOperator(input[offset:])
+			if !ok {
+				return nil, errorAt(input, offset, "unexpected character %q", r)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op, offset: offset})
+			offset += len(op)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, offset: len(input)}), nil
+}
+
+// lexString reads a double-quoted string starting at offset, where \" and \\ are escapes, and
+// returns its contents and the offset just past the closing quote
+func lexString(input string, offset int) (string, int, error) {
+	var b strings.Builder
+	for i := offset + 1; i < len(input); i++ {
+		switch input[i] {
+		case '"':
+			return b.String(), i + 1, nil
+		case '\\':
+			if i+1 < len(input) && (input[i+1] == '"' || input[i+1] == '\\') {
+				i++
+			}
+		}
+		b.WriteByte(input[i])
+	}
+	return "", 0, errorAt(input, offset, "unterminated string")
+}
+
+// lexOperator matches the comparison operator at the start of s
+func lexOperator(s string) (string, bool) {
+	for _, op := range operators {
+		if strings.HasPrefix(s, op) {
+			return op, true
+		}
+	}
+	return "", false
+}
+
+// isWordRune reports whether r may appear in a bare word: letters, digits and . _ - +
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("._-+", r)
+}
@@ -0,0 +1,205 @@
+package filterexpr
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MaxLength and MaxDepth bound the size of an expression, keeping parsing and evaluation cheap
+const (
+	MaxLength = 1000
+	MaxDepth  = 32
+)
+
+// Parse parses a filter expression and type-checks it against schema. The grammar, with
+// keywords matched case-insensitively:
+//
+//	expression = term { "OR" term }
+//	term       = factor { "AND" factor }
+//	factor     = "NOT" factor | "(" expression ")" | comparison
+//	comparison = field operator value
+//	operator   = ":" | "=" | "!=" | "<" | "<=" | ">" | ">=" | "~"
+//	value      = number | word | "\"" characters "\""
+//
+// ":" and "=" test equality (case-insensitive for strings), "~" tests that a string field
+// contains the value, and the order operators apply to number fields. Errors are *Error values
+// reporting the column they occur at.
+func Parse(input string, schema Schema) (Node, error) {
+	if len(input) > MaxLength {
+		return nil, &Error{Column: MaxLength + 1, Message: "filter is longer than " + strconv.Itoa(MaxLength) + " characters"}
+	}
+
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{input: input, tokens: tokens, schema: schema}
+	if p.peek().kind == tokenEOF {
+		return nil, p.errorf("empty filter")
+	}
+	node, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenEOF {
+		return nil, p.errorf("expected AND, OR or end of filter, found %s", describe(p.peek()))
+	}
+	return node, nil
+}
+
+// parser is a recursive-descent parser over a token list
+type parser struct {
+	input  string
+	tokens []token
+	pos    int
+	depth  int
+	schema Schema
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// errorf reports an error at the current token
+func (p *parser) errorf(format string, args ...any) error {
+	return p.errorAtToken(p.peek(), format, args...)
+}
+
+func (p *parser) errorAtToken(t token, format string, args ...any) error {
+	return errorAt(p.input, t.offset, format, args...)
+}
+
+func (p *parser) expression() (Node, error) {
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for keyword(p.peek(), "OR") {
+		p.next()
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) term() (Node, error) {
+	left, err := p.factor()
+	if err != nil {
+		return nil, err
+	}
+	for keyword(p.peek(), "AND") {
+		p.next()
+		right, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) factor() (Node, error) {
+	if p.depth >= MaxDepth {
+		return nil, p.errorf("filter is nested more than %d levels deep", MaxDepth)
+	}
+	p.depth++
+	defer func() { p.depth-- }()
+
+	switch t := p.peek(); {
+	case keyword(t, "NOT"):
+		p.next()
+		operand, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Operand: operand}, nil
+	case t.kind == tokenLParen:
+		p.next()
+		node, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokenRParen {
+			return nil, p.errorf("expected ), found %s", describe(p.peek()))
+		}
+		p.next()
+		return node, nil
+	default:
+		return p.comparison()
+	}
+}
+
+func (p *parser) comparison() (Node, error) {
+	field := p.next()
+	if field.kind != tokenWord {
+		return nil, p.errorAtToken(field, "expected a field name, found %s", describe(field))
+	}
+	name := strings.ToLower(field.text)
+	fieldType, ok := p.schema[name]
+	if !ok {
+		return nil, p.errorAtToken(field, "unknown field %q; allowed fields are %s", field.text, strings.Join(p.fieldNames(), ", "))
+	}
+
+	opToken := p.next()
+	if opToken.kind != tokenOperator {
+		return nil, p.errorAtToken(opToken, "expected an operator after %s, found %s", name, describe(opToken))
+	}
+	op := Operator(opToken.text)
+	if op == ":" {
+		op = Equal
+	}
+	if !op.supports(fieldType) {
+		return nil, p.errorAtToken(opToken, "operator %s is not supported for %s field %s", opToken.text, fieldType, name)
+	}
+
+	literal := p.next()
+	if literal.kind != tokenWord && literal.kind != tokenString {
+		return nil, p.errorAtToken(literal, "expected a value after %s%s, found %s", name, opToken.text, describe(literal))
+	}
+	value := StringValue(literal.text)
+	if fieldType == Number {
+		n, err := strconv.ParseFloat(literal.text, 64)
+		if err != nil || literal.kind == tokenString || math.IsInf(n, 0) || math.IsNaN(n) {
+			return nil, p.errorAtToken(literal, "field %s expects a number, found %s", name, describe(literal))
+		}
+		value = NumberValue(n)
+	}
+
+	return &Comparison{Field: name, Op: op, Value: value, Column: column(p.input, field.offset)}, nil
+}
+
+// fieldNames lists the schema's fields alphabetically
+func (p *parser) fieldNames() []string {
+	names := make([]string, 0, len(p.schema))
+	for name := range p.schema {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// describe names a token for error messages
+func describe(t token) string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of filter"
+	case tokenString:
+		return strconv.Quote(t.text)
+	default:
+		return "'" + t.text + "'"
+	}
+}
@@ -0,0 +1,82 @@
+package filterexpr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SQL compiles node to a SQL boolean expression with ? placeholders and their arguments, for
+// backends that filter in the database. columns maps field names to trusted column expressions,
+// which are NULL where the record has no value; string comparisons are case-insensitive. Each
+// comparison is guarded with IS NOT NULL so it is false rather than NULL for a missing value, and
+// NOT then matches such rows, as in Eval.
+func SQL(node Node, columns map[string]string) (string, []any, error) {
+	var b strings.Builder
+	var args []any
+	if err := writeSQL(&b, &args, node, columns); err != nil {
+		return "", nil, err
+	}
+	return b.String(), args, nil
+}
+
+func writeSQL(b *strings.Builder, args *[]any, node Node, columns map[string]string) error {
+	switch n := node.(type) {
+	case *And:
+		return writeBinarySQL(b, args, n.Left, "AND", n.Right, columns)
+	case *Or:
+		return writeBinarySQL(b, args, n.Left, "OR", n.Right, columns)
+	case *Not:
+		b.WriteString("NOT ")
+		return writeSQL(b, args, n.Operand, columns)
+	case *Comparison:
+		column, ok := columns[n.Field]
+		if !ok {
+			return &Error{Column: n.Column, Message: fmt.Sprintf("field %s has no SQL column", n.Field)}
+		}
+
+		fmt.Fprintf(b, "(%s IS NOT NULL AND ", column)
+		defer b.WriteString(")")
+
+		if n.Value.Type == Number {
+			op := string(n.Op)
+			if n.Op == NotEqual {
+				op = "<>"
+			}
+			fmt.Fprintf(b, "%s %s ?", column, op)
+			*args = append(*args, n.Value.Number)
+			return nil
+		}
+
+		switch n.Op {
+		case Equal:
+			fmt.Fprintf(b, "LOWER(%s) = LOWER(?)", column)
+			*args = append(*args, n.Value.Text)
+		case NotEqual:
+			fmt.Fprintf(b, "LOWER(%s) <> LOWER(?)", column)
+			*args = append(*args, n.Value.Text)
+		case Contains:
+			fmt.Fprintf(b, "LOWER(%s) LIKE ? ESCAPE '\\'", column)
+			*args = append(*args, "%"+escapeLike(strings.ToLower(n.Value.Text))+"%")
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported filter node %T", node)
+}
+
+func writeBinarySQL(b *strings.Builder, args *[]any, left Node, op string, right Node, columns map[string]string) error {
+	b.WriteString("(")
+	if err := writeSQL(b, args, left, columns); err != nil {
+		return err
+	}
+	b.WriteString(" " + op + " ")
+	if err := writeSQL(b, args, right, columns); err != nil {
+		return err
+	}
+	b.WriteString(")")
+	return nil
+}
+
+// escapeLike escapes LIKE wildcards so the value matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
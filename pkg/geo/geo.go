@@ -0,0 +1,85 @@
+// Package geo provides great-circle distances, bounding boxes and a geohash grid index for
+// finding points near a location without scanning every point.
+package geo
+
+import "math"
+
+// earthRadiusKm is the mean radius of the Earth
+const earthRadiusKm = 6371.0088
+
+// Point is a location in decimal degrees
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// Valid reports whether the point's latitude and longitude are in range
+func (p Point) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lng >= -180 && p.Lng <= 180
+}
+
+// DistanceKm returns the great-circle distance between a and b in kilometres (haversine)
+func DistanceKm(a, b Point) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLat := lat2 - lat1
+	dLng := radians(b.Lng - a.Lng)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// BoundingBox is a latitude/longitude rectangle, edges included. Boxes crossing the
+// antimeridian are not supported.
+type BoundingBox struct {
+	MinLat, MinLng, MaxLat, MaxLng float64
+}
+
+// World is the bounding box covering every point
+var World = BoundingBox{MinLat: -90, MinLng: -180, MaxLat: 90, MaxLng: 180}
+
+// Contains reports whether p lies within the box
+func (b BoundingBox) Contains(p Point) bool {
+	return p.Lat >= b.MinLat && p.Lat <= b.MaxLat && p.Lng >= b.MinLng && p.Lng <= b.MaxLng
+}
+
+// Intersect returns the overlap of two boxes; an empty overlap has Min above Max
+func (b BoundingBox) Intersect(other BoundingBox) BoundingBox {
+	return BoundingBox{
+		MinLat: math.Max(b.MinLat, other.MinLat),
+		MinLng: math.Max(b.MinLng, other.MinLng),
+		MaxLat: math.Min(b.MaxLat, other.MaxLat),
+		MaxLng: math.Min(b.MaxLng, other.MaxLng),
+	}
+}
+
+// Empty reports whether the box contains no points
+func (b BoundingBox) Empty() bool {
+	return b.MinLat > b.MaxLat || b.MinLng > b.MaxLng
+}
+
+// Around returns a box enclosing the circle of radiusKm around center. Near the poles, where
+// the circle reaches them, it spans every longitude.
+func Around(center Point, radiusKm float64) BoundingBox {
+	dLat := degrees(radiusKm / earthRadiusKm)
+	box := BoundingBox{
+		MinLat: math.Max(-90, center.Lat-dLat),
+		MaxLat: math.Min(90, center.Lat+dLat),
+		MinLng: -180,
+		MaxLng: 180,
+	}
+	if box.MinLat > -90 && box.MaxLat < 90 {
+		// The widest point of the circle in longitude is at the latitude furthest from the equator
+		dLng := degrees(math.Asin(math.Min(1, math.Sin(radiusKm/earthRadiusKm)/math.Cos(radians(center.Lat)))))
+		box.MinLng = math.Max(-180, center.Lng-dLng)
+		box.MaxLng = math.Min(180, center.Lng+dLng)
+	}
+	return box
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
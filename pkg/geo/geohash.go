@@ -0,0 +1,144 @@
+package geo
+
+import (
+	"math"
+	"strings"
+)
+
+// base32 is the geohash alphabet
+const base32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// Encode returns the geohash of p with precision characters. Each character halves the cell
+// five times, alternating longitude and latitude, so precision 5 cells are about 4.9 x 4.9 km.
+func Encode(p Point, precision int) string {
+	var b strings.Builder
+	minLat, maxLat := -90.0, 90.0
+	minLng, maxLng := -180.0, 180.0
+	even := true
+	bit, ch := 0, 0
+
+	for b.Len() < precision {
+		if even {
+			mid := (minLng + maxLng) / 2
+			if p.Lng >= mid {
+				ch = ch<<1 | 1
+				minLng = mid
+			} else {
+				ch <<= 1
+				maxLng = mid
+			}
+		} else {
+			mid := (minLat + maxLat) / 2
+			if p.Lat >= mid {
+				ch = ch<<1 | 1
+				minLat = mid
+			} else {
+				ch <<= 1
+				maxLat = mid
+			}
+		}
+		even = !even
+
+		if bit++; bit == 5 {
+			b.WriteByte(base32[ch])
+			bit, ch = 0, 0
+		}
+	}
+	return b.String()
+}
+
+// CellSize returns the height and width in degrees of geohash cells of the given precision
+func CellSize(precision int) (lat, lng float64) {
+	bits := 5 * precision
+	lngBits := (bits + 1) / 2
+	latBits := bits / 2
+	return 180 / math.Exp2(float64(latBits)), 360 / math.Exp2(float64(lngBits))
+}
+
+// Bounds returns the cell a geohash denotes
+func Bounds(hash string) BoundingBox {
+	box := World
+	even := true
+	for i := 0; i < len(hash); i++ {
+		ch := strings.IndexByte(base32, hash[i])
+		for bit := 4; bit >= 0; bit-- {
+			on := ch>>bit&1 == 1
+			if even {
+				mid := (box.MinLng + box.MaxLng) / 2
+				if on {
+					box.MinLng = mid
+				} else {
+					box.MaxLng = mid
+				}
+			} else {
+				mid := (box.MinLat + box.MaxLat) / 2
+				if on {
+					box.MinLat = mid
+				} else {
+					box.MaxLat = mid
+				}
+			}
+			even = !even
+		}
+	}
+	return box
+}
+
+// Grid indexes points by their geohash cell, so points within a region are found by visiting the
+// cells covering it rather than every point. It is not safe for concurrent modification; build it
+// once and then only query it.
+type Grid struct {
+	precision int
+	cells     map[string][]int
+}
+
+// NewGrid creates a grid of geohash cells with the given precision
+func NewGrid(precision int) *Grid {
+	return &Grid{precision: precision, cells: make(map[string][]int)}
+}
+
+// Add indexes the value id (typically a slice index) at p
+func (g *Grid) Add(id int, p Point) {
+	hash := Encode(p, g.precision)
+	g.cells[hash] = append(g.cells[hash], id)
+}
+
+// Candidates returns the ids in the cells overlapping box, a superset of the ids whose points lie
+// in it, in no particular order. It visits the cells covering box, or for a box covering more
+// cells than the grid has occupied, the occupied cells, so it never touches individual points
+// outside the overlapping cells.
+func (g *Grid) Candidates(box BoundingBox) []int {
+	if box.Empty() {
+		return nil
+	}
+
+	var ids []int
+	cellLat, cellLng := CellSize(g.precision)
+	firstRow, lastRow := cellIndex(box.MinLat, -90, cellLat), cellIndex(box.MaxLat, -90, cellLat)
+	firstCol, lastCol := cellIndex(box.MinLng, -180, cellLng), cellIndex(box.MaxLng, -180, cellLng)
+	// The north and east edges belong to the last row and column
+	lastRow = min(lastRow, int(180/cellLat)-1)
+	lastCol = min(lastCol, int(360/cellLng)-1)
+
+	if (lastRow-firstRow+1)*(lastCol-firstCol+1) > len(g.cells) {
+		for hash, cellIDs := range g.cells {
+			if !Bounds(hash).Intersect(box).Empty() {
+				ids = append(ids, cellIDs...)
+			}
+		}
+		return ids
+	}
+
+	for row := firstRow; row <= lastRow; row++ {
+		for col := firstCol; col <= lastCol; col++ {
+			center := Point{Lat: -90 + (float64(row)+0.5)*cellLat, Lng: -180 + (float64(col)+0.5)*cellLng}
+			ids = append(ids, g.cells[Encode(center, g.precision)]...)
+		}
+	}
+	return ids
+}
+
+// cellIndex returns the index of the cell of size containing value, counting from origin
+func cellIndex(value, origin, size float64) int {
+	return int(math.Floor((value - origin) / size))
+}
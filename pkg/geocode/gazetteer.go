@@ -0,0 +1,239 @@
+// Package geocode resolves place names to coordinates offline, from a gazetteer of states,
+// cities and neighbourhoods with their centroids.
+package geocode
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"housing-api/pkg/geo"
+	"housing-api/pkg/search"
+)
+
+// Precisions of a match: the level of the gazetteer place it came from
+const (
+	PrecisionState         = "state"
+	PrecisionCity          = "city"
+	PrecisionNeighbourhood = "neighbourhood"
+)
+
+// File is the editable gazetteer file: states, the cities in them and the neighbourhoods in those
+type File struct {
+	States []Place `json:"states"`
+}
+
+// Place is a named area with its centroid. Aliases are other spellings of the name ("Wuse II",
+// "VI"); Places are the areas within it. Neighbourhoods may nest (Chevron within Lekki).
+type Place struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases,omitempty"`
+	Lat     float64  `json:"lat"`
+	Lng     float64  `json:"lng"`
+	Places  []Place  `json:"places,omitempty"`
+}
+
+// Validate reports every unnamed place, missing or out of range centroid and name shared by two
+// places with the same parent
+func (f File) Validate() error {
+	var errs []error
+	var check func(path string, places []Place)
+	check = func(path string, places []Place) {
+		seen := make(map[string]string)
+		for i, place := range places {
+			at := fmt.Sprintf("%s[%d]", path, i)
+			if key(place.Name) == "" {
+				errs = append(errs, fmt.Errorf("%s: a place needs a name", at))
+			} else {
+				at = fmt.Sprintf("%s (%s)", at, place.Name)
+			}
+
+			point := geo.Point{Lat: place.Lat, Lng: place.Lng}
+			if !point.Valid() || point == (geo.Point{}) {
+				errs = append(errs, fmt.Errorf("%s: lat and lng must locate the place's centre", at))
+			}
+
+			for _, name := range append([]string{place.Name}, place.Aliases...) {
+				k := key(name)
+				if k == "" {
+					if name != place.Name {
+						errs = append(errs, fmt.Errorf("%s: empty alias %q", at, name))
+					}
+					continue
+				}
+				if other, ok := seen[k]; ok && other != at {
+					errs = append(errs, fmt.Errorf("%s: %q is also a name of %s", at, name, other))
+				}
+				seen[k] = at
+			}
+
+			check(at+".places", place.Places)
+		}
+	}
+	check("states", f.States)
+	return errors.Join(errs...)
+}
+
+// Match is a resolved location
+type Match struct {
+	Point geo.Point `json:"point"`
+
+	// Place is the matched place and its parents, e.g. "Lekki Phase 1, Lekki, Lagos, Lagos"
+	Place string `json:"place"`
+
+	// Precision is the level of the matched place
+	Precision string `json:"precision"`
+}
+
+// Gazetteer looks up places by name or alias. Names are compared as folded words, so case,
+// diacritics and punctuation don't matter ("Abule-Egba" matches "Abule Egba"). A nil *Gazetteer
+// resolves nothing.
+type Gazetteer struct {
+	file   File
+	byName map[string][]*node
+	size   int
+}
+
+// node is a place in the gazetteer tree
+type node struct {
+	name     string
+	keys     []string
+	point    geo.Point
+	depth    int
+	parent   *node
+	children []*node
+}
+
+// NewGazetteer indexes f for lookups; f should be valid
+func NewGazetteer(f File) *Gazetteer {
+	g := &Gazetteer{file: f, byName: make(map[string][]*node)}
+
+	var add func(places []Place, parent *node, depth int) []*node
+	add = func(places []Place, parent *node, depth int) []*node {
+		nodes := make([]*node, 0, len(places))
+		for _, place := range places {
+			n := &node{name: place.Name, point: geo.Point{Lat: place.Lat, Lng: place.Lng}, depth: depth, parent: parent}
+			n.children = add(place.Places, n, depth+1)
+			nodes = append(nodes, n)
+			g.size++
+
+			for _, name := range append([]string{place.Name}, place.Aliases...) {
+				if k := key(name); k != "" && !slices.Contains(n.keys, k) {
+					n.keys = append(n.keys, k)
+					g.byName[k] = append(g.byName[k], n)
+				}
+			}
+		}
+		return nodes
+	}
+	add(f.States, nil, 0)
+
+	for _, nodes := range g.byName {
+		sort.SliceStable(nodes, func(i, j int) bool { return rank(nodes[i]) < rank(nodes[j]) })
+	}
+	return g
+}
+
+// File returns the gazetteer file the gazetteer was built from
+func (g *Gazetteer) File() File {
+	if g == nil {
+		return File{States: []Place{}}
+	}
+	return g.file
+}
+
+// Len returns the number of places in the gazetteer
+func (g *Gazetteer) Len() int {
+	if g == nil {
+		return 0
+	}
+	return g.size
+}
+
+// Resolve locates an area within a city, as split from a listing's "Area, City" location. The
+// city may name a city, a state or a neighbourhood ("Chevron, Lekki"). The area is looked up
+// within the city, then within the places around it up to the state; an area the gazetteer
+// doesn't know falls back to the city's centroid. With an unknown city, an area whose name is
+// unique in the gazetteer still resolves.
+func (g *Gazetteer) Resolve(area, city string) (Match, bool) {
+	if g == nil {
+		return Match{}, false
+	}
+	areaKey, cityKey := key(area), key(city)
+
+	cities := g.byName[cityKey]
+	if areaKey != cityKey {
+		for _, c := range cities {
+			for within := c; within != nil; within = within.parent {
+				if n := within.find(areaKey); n != nil {
+					return n.match(), true
+				}
+			}
+		}
+	}
+	if len(cities) > 0 {
+		return cities[0].match(), true
+	}
+
+	if areas := g.byName[areaKey]; len(areas) == 1 {
+		return areas[0].match(), true
+	}
+	return Match{}, false
+}
+
+// find returns the shallowest place below n named or aliased k
+func (n *node) find(k string) *node {
+	level := n.children
+	for len(level) > 0 {
+		var next []*node
+		for _, child := range level {
+			if slices.Contains(child.keys, k) {
+				return child
+			}
+			next = append(next, child.children...)
+		}
+		level = next
+	}
+	return nil
+}
+
+// match describes n as a Match
+func (n *node) match() Match {
+	names := []string{}
+	for p := n; p != nil; p = p.parent {
+		names = append(names, p.name)
+	}
+	return Match{Point: n.point, Place: strings.Join(names, ", "), Precision: precision(n.depth)}
+}
+
+// precision names the level of a place at depth in the tree
+func precision(depth int) string {
+	switch depth {
+	case 0:
+		return PrecisionState
+	case 1:
+		return PrecisionCity
+	default:
+		return PrecisionNeighbourhood
+	}
+}
+
+// rank orders places sharing a name: "Lagos" means the city before the state, and either before
+// a neighbourhood of the same name
+func rank(n *node) int {
+	switch n.depth {
+	case 1:
+		return 0
+	case 0:
+		return 1
+	default:
+		return n.depth
+	}
+}
+
+// key normalises a place name for lookups
+func key(name string) string {
+	return strings.Join(search.Words(name), " ")
+}
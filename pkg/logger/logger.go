@@ -1,19 +1,30 @@
 package logger
 
 import (
+	"context"
 	"os"
 
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
 )
 
-var log *logrus.Logger
+// log defaults to a plain logrus logger so packages can log before Init runs (e.g. in tests)
+var log = logrus.New()
+
+type contextKey string
+
+const requestIDKey contextKey = "request_id"
 
 // Init initializes the logger
 func Init(level string) {
 	log = logrus.New()
 	log.SetOutput(os.Stdout)
 	log.SetFormatter(&logrus.JSONFormatter{})
+	SetLevel(level)
+}
 
+// SetLevel changes the log level at runtime; unknown levels fall back to info
+func SetLevel(level string) {
 	switch level {
 	case "debug":
 		log.SetLevel(logrus.DebugLevel)
@@ -28,6 +39,20 @@ func Init(level string) {
 	}
 }
 
+// WithRequestID returns a copy of ctx carrying the given request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx, or an empty string
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
 func Debug(msg string, fields ...interface{}) {
 	log.WithFields(parseFields(fields...)).Debug(msg)
 }
@@ -44,6 +69,26 @@ func Error(msg string, fields ...interface{}) {
 	log.WithFields(parseFields(fields...)).Error(msg)
 }
 
+// DebugContext logs at debug level, tagging the entry with the request and trace IDs from ctx
+func DebugContext(ctx context.Context, msg string, fields ...interface{}) {
+	log.WithFields(contextFields(ctx, fields...)).Debug(msg)
+}
+
+// InfoContext logs at info level, tagging the entry with the request and trace IDs from ctx
+func InfoContext(ctx context.Context, msg string, fields ...interface{}) {
+	log.WithFields(contextFields(ctx, fields...)).Info(msg)
+}
+
+// WarnContext logs at warn level, tagging the entry with the request and trace IDs from ctx
+func WarnContext(ctx context.Context, msg string, fields ...interface{}) {
+	log.WithFields(contextFields(ctx, fields...)).Warn(msg)
+}
+
+// ErrorContext logs at error level, tagging the entry with the request and trace IDs from ctx
+func ErrorContext(ctx context.Context, msg string, fields ...interface{}) {
+	log.WithFields(contextFields(ctx, fields...)).Error(msg)
+}
+
 // parseFields converts variadic arguments to logrus.Fields
 func parseFields(fields ...interface{}) logrus.Fields {
 	logFields := logrus.Fields{}
@@ -56,3 +101,19 @@ func parseFields(fields ...interface{}) logrus.Fields {
 	}
 	return logFields
 }
+
+// contextFields converts variadic arguments to logrus.Fields and adds correlation data from ctx
+func contextFields(ctx context.Context, fields ...interface{}) logrus.Fields {
+	logFields := parseFields(fields...)
+	if ctx == nil {
+		return logFields
+	}
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		logFields["request_id"] = requestID
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		logFields["trace_id"] = spanContext.TraceID().String()
+		logFields["span_id"] = spanContext.SpanID().String()
+	}
+	return logFields
+}
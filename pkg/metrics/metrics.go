@@ -0,0 +1,142 @@
+package metrics
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "housing_api"
+
+var (
+	registry = prometheus.NewRegistry()
+
+	httpRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Total number of HTTP requests by route, method and status.",
+	}, []string{"route", "method", "status"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	rateLimitRejectionsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limit_rejections_total",
+		Help:      "Total number of requests rejected by the rate limiter.",
+	})
+
+	authFailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_failures_total",
+		Help:      "Total number of authentication failures by reason.",
+	}, []string{"reason"})
+
+	listingsLoaded = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "listings_loaded",
+		Help:      "Number of listings currently loaded in memory.",
+	})
+
+	dataReloadsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "data_reloads_total",
+		Help:      "Total number of listing data reloads.",
+	})
+
+	cacheLookupsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_lookups_total",
+		Help:      "Total number of in-process cache lookups by cache and result (hit or miss).",
+	}, []string{"cache", "result"})
+
+	cacheEntries = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cache_entries",
+		Help:      "Number of entries currently held by each in-process cache.",
+	}, []string{"cache"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestsTotal,
+		httpRequestDuration,
+		rateLimitRejectionsTotal,
+		authFailuresTotal,
+		listingsLoaded,
+		dataReloadsTotal,
+		cacheLookupsTotal,
+		cacheEntries,
+	)
+}
+
+// ObserveRequest records the count and latency of a completed HTTP request
+func ObserveRequest(route, method string, status int, duration time.Duration) {
+	statusLabel := strconv.Itoa(status)
+	httpRequestsTotal.WithLabelValues(route, method, statusLabel).Inc()
+	httpRequestDuration.WithLabelValues(route, method, statusLabel).Observe(duration.Seconds())
+}
+
+// IncRateLimitRejections records a request rejected by the rate limiter
+func IncRateLimitRejections() {
+	rateLimitRejectionsTotal.Inc()
+}
+
+// IncAuthFailure records an authentication failure with the given reason
+func IncAuthFailure(reason string) {
+	authFailuresTotal.WithLabelValues(reason).Inc()
+}
+
+// SetListingsLoaded records the number of listings currently in memory
+func SetListingsLoaded(count int) {
+	listingsLoaded.Set(float64(count))
+}
+
+// IncDataReloads records a listing data reload
+func IncDataReloads() {
+	dataReloadsTotal.Inc()
+}
+
+// IncCacheLookup records a cache lookup as a hit or a miss
+func IncCacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheLookupsTotal.WithLabelValues(cache, result).Inc()
+}
+
+// SetCacheEntries records the number of entries a cache holds
+func SetCacheEntries(cache string, count int) {
+	cacheEntries.WithLabelValues(cache).Set(float64(count))
+}
+
+// Handler returns an HTTP handler serving metrics in Prometheus text format.
+// When token is non-empty, requests must carry it as a Bearer token.
+func Handler(token string) http.Handler {
+	handler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+	if token == "" {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,361 @@
+package odata
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"housing-api/pkg/filterexpr"
+)
+
+// Field maps an OData property onto a filter expression field of the same type
+type Field struct {
+	Name string
+	Type filterexpr.Type
+}
+
+// comparisonOperators maps OData comparison operators onto filter expression operators
+var comparisonOperators = map[string]filterexpr.Operator{
+	"eq": filterexpr.Equal,
+	"ne": filterexpr.NotEqual,
+	"lt": filterexpr.Less,
+	"le": filterexpr.LessOrEqual,
+	"gt": filterexpr.Greater,
+	"ge": filterexpr.GreaterOrEqual,
+}
+
+// ParseFilter translates a $filter expression into a filter expression over the mapped fields.
+// The supported subset of OData:
+//
+//	expression = term { "or" term }
+//	term       = factor { "and" factor }
+//	factor     = "not" factor | "(" expression ")" | "contains(" property "," string ")" | comparison
+//	comparison = property ( "eq" | "ne" | "lt" | "le" | "gt" | "ge" ) literal
+//	literal    = number | "'" characters "'"
+//
+// Property names are case-sensitive; quotes inside strings are doubled (”). Errors are
+// *filterexpr.Error values reporting the column they occur at.
+func ParseFilter(input string, fields map[string]Field) (filterexpr.Node, error) {
+	if len(input) > filterexpr.MaxLength {
+		return nil, &filterexpr.Error{Column: filterexpr.MaxLength + 1, Message: fmt.Sprintf("$filter is longer than %d characters", filterexpr.MaxLength)}
+	}
+
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{input: input, tokens: tokens, fields: fields}
+	if p.peek().kind == tokenEOF {
+		return nil, p.errorf(p.peek(), "empty $filter")
+	}
+	node, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenEOF {
+		return nil, p.errorf(p.peek(), "expected and, or or end of $filter, found %s", describe(p.peek()))
+	}
+	return node, nil
+}
+
+// tokenKind classifies a lexical token
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdentifier
+	tokenNumber
+	tokenString
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+// token is a lexical token and the byte offset it starts at
+type token struct {
+	kind   tokenKind
+	text   string
+	offset int
+}
+
+// lex splits input into identifiers, numbers, single-quoted strings, parentheses and commas
+func lex(input string) ([]token, error) {
+	var tokens []token
+
+	for offset := 0; offset < len(input); {
+		r, size := utf8.DecodeRuneInString(input[offset:])
+		switch {
+		case unicode.IsSpace(r):
+			offset += size
+		case r == '(' || r == ')' || r == ',':
+			kind := map[rune]tokenKind{'(': tokenLParen, ')': tokenRParen, ',': tokenComma}[r]
+			tokens = append(tokens, token{kind: kind, text: string(r), offset: offset})
+			offset += size
+		case r == '\'':
+			text, end, err := lexString(input, offset)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, offset: offset})
+			offset = end
+		case unicode.IsDigit(r) || r == '-' || r == '.':
+			end := scan(input, offset, func(r rune) bool {
+				return unicode.IsDigit(r) || strings.ContainsRune("-+.eE", r)
+			})
+			tokens = append(tokens, token{kind: tokenNumber, text: input[offset:end], offset: offset})
+			offset = end
+		case unicode.IsLetter(r) || r == '_':
+			end := scan(input, offset, func(r rune) bool {
+				return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+			})
+			tokens = append(tokens, token{kind: tokenIdentifier, text: input[offset:end], offset: offset})
+			offset = end
+		default:
+			return nil, errorAt(input, offset, "unexpected character %q", r)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, offset: len(input)}), nil
+}
+
+// scan returns the offset of the first rune from offset on that doesn't satisfy accept
+func scan(input string, offset int, accept func(rune) bool) int {
+	for offset < len(input) {
+		r, size := utf8.DecodeRuneInString(input[offset:])
+		if !accept(r) {
+			break
+		}
+		offset += size
+	}
+	return offset
+}
+
+// lexString reads a single-quoted string starting at offset, where ” is an escaped quote, and
+// returns its contents and the offset just past the closing quote
+func lexString(input string, offset int) (string, int, error) {
+	var b strings.Builder
+	for i := offset + 1; i < len(input); i++ {
+		if input[i] == '\'' {
+			if i+1 < len(input) && input[i+1] == '\'' {
+				b.WriteByte('\'')
+				i++
+				continue
+			}
+			return b.String(), i + 1, nil
+		}
+		b.WriteByte(input[i])
+	}
+	return "", 0, errorAt(input, offset, "unterminated string")
+}
+
+// parser is a recursive-descent parser over a token list
+type parser struct {
+	input  string
+	tokens []token
+	pos    int
+	depth  int
+	fields map[string]Field
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// expect consumes a token of the given kind or reports what was found instead
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	t := p.next()
+	if t.kind != kind {
+		return t, p.errorf(t, "expected %s, found %s", what, describe(t))
+	}
+	return t, nil
+}
+
+func (p *parser) errorf(t token, format string, args ...any) error {
+	return errorAt(p.input, t.offset, format, args...)
+}
+
+// keyword reports whether the next token is the given lowercase OData keyword
+func (p *parser) keyword(word string) bool {
+	t := p.peek()
+	return t.kind == tokenIdentifier && t.text == word
+}
+
+func (p *parser) expression() (filterexpr.Node, error) {
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		p.next()
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		left = &filterexpr.Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) term() (filterexpr.Node, error) {
+	left, err := p.factor()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		p.next()
+		right, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		left = &filterexpr.And{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) factor() (filterexpr.Node, error) {
+	if p.depth >= filterexpr.MaxDepth {
+		return nil, p.errorf(p.peek(), "$filter is nested more than %d levels deep", filterexpr.MaxDepth)
+	}
+	p.depth++
+	defer func() { p.depth-- }()
+
+	switch t := p.peek(); {
+	case p.keyword("not"):
+		p.next()
+		operand, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		return &filterexpr.Not{Operand: operand}, nil
+	case t.kind == tokenLParen:
+		p.next()
+		node, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenRParen, ")"); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case t.kind == tokenIdentifier && p.tokens[p.pos+1].kind == tokenLParen:
+		return p.function()
+	default:
+		return p.comparison()
+	}
+}
+
+// function parses contains(property, 'text'), the only supported function
+func (p *parser) function() (filterexpr.Node, error) {
+	name := p.next()
+	if name.text != "contains" {
+		return nil, p.errorf(name, "unsupported function %s; only contains is supported", name.text)
+	}
+	p.next()
+
+	property, field, err := p.property()
+	if err != nil {
+		return nil, err
+	}
+	if field.Type != filterexpr.String {
+		return nil, p.errorf(property, "contains requires a string property, %s is a number", property.text)
+	}
+	if _, err := p.expect(tokenComma, ","); err != nil {
+		return nil, err
+	}
+	literal, err := p.expect(tokenString, "a string")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.expect(tokenRParen, ")"); err != nil {
+		return nil, err
+	}
+
+	return &filterexpr.Comparison{
+		Field:  field.Name,
+		Op:     filterexpr.Contains,
+		Value:  filterexpr.StringValue(literal.text),
+		Column: column(p.input, property.offset),
+	}, nil
+}
+
+func (p *parser) comparison() (filterexpr.Node, error) {
+	property, field, err := p.property()
+	if err != nil {
+		return nil, err
+	}
+
+	opToken := p.next()
+	op, ok := comparisonOperators[opToken.text]
+	if opToken.kind != tokenIdentifier || !ok {
+		return nil, p.errorf(opToken, "expected eq, ne, lt, le, gt or ge after %s, found %s", property.text, describe(opToken))
+	}
+	if field.Type == filterexpr.String && op != filterexpr.Equal && op != filterexpr.NotEqual {
+		return nil, p.errorf(opToken, "operator %s is not supported for string property %s", opToken.text, property.text)
+	}
+
+	literal := p.next()
+	var value filterexpr.Value
+	switch {
+	case field.Type == filterexpr.String && literal.kind == tokenString:
+		value = filterexpr.StringValue(literal.text)
+	case field.Type == filterexpr.Number && literal.kind == tokenNumber:
+		n, err := strconv.ParseFloat(literal.text, 64)
+		if err != nil || math.IsInf(n, 0) {
+			return nil, p.errorf(literal, "invalid number %s", literal.text)
+		}
+		value = filterexpr.NumberValue(n)
+	case field.Type == filterexpr.String:
+		return nil, p.errorf(literal, "property %s expects a string literal such as 'Lagos', found %s", property.text, describe(literal))
+	default:
+		return nil, p.errorf(literal, "property %s expects a number, found %s", property.text, describe(literal))
+	}
+
+	return &filterexpr.Comparison{Field: field.Name, Op: op, Value: value, Column: column(p.input, property.offset)}, nil
+}
+
+// property consumes a property name and returns its mapping
+func (p *parser) property() (token, Field, error) {
+	t, err := p.expect(tokenIdentifier, "a property name")
+	if err != nil {
+		return t, Field{}, err
+	}
+	field, ok := p.fields[t.text]
+	if !ok {
+		return t, Field{}, p.errorf(t, "unknown or non-filterable property %s", t.text)
+	}
+	return t, field, nil
+}
+
+// describe names a token for error messages
+func describe(t token) string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of $filter"
+	case tokenString:
+		return "'" + strings.ReplaceAll(t.text, "'", "''") + "'"
+	default:
+		return t.text
+	}
+}
+
+// errorAt returns an error at the byte offset in input
+func errorAt(input string, offset int, format string, args ...any) error {
+	return &filterexpr.Error{Column: column(input, offset), Message: fmt.Sprintf(format, args...)}
+}
+
+// column converts a byte offset in input to a 1-based character column
+func column(input string, offset int) int {
+	return utf8.RuneCountInString(input[:offset]) + 1
+}
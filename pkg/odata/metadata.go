@@ -0,0 +1,94 @@
+package odata
+
+import "encoding/xml"
+
+// EntityType describes an entity for the $metadata document
+type EntityType struct {
+	Name       string
+	Key        string
+	Properties []Property
+}
+
+// Property is a structural property of an entity type; Type is an EDM type such as Edm.String
+type Property struct {
+	Name     string
+	Type     string
+	Nullable bool
+}
+
+// edmx is the CSDL XML layout of a $metadata document
+type edmx struct {
+	XMLName  xml.Name `xml:"edmx:Edmx"`
+	XMLNS    string   `xml:"xmlns:edmx,attr"`
+	Version  string   `xml:"Version,attr"`
+	Services struct {
+		Schema edmSchema `xml:"Schema"`
+	} `xml:"edmx:DataServices"`
+}
+
+type edmSchema struct {
+	XMLNS       string          `xml:"xmlns,attr"`
+	Namespace   string          `xml:"Namespace,attr"`
+	EntityTypes []edmEntityType `xml:"EntityType"`
+	Container   edmContainer    `xml:"EntityContainer"`
+}
+
+type edmEntityType struct {
+	Name string `xml:"Name,attr"`
+	Key  struct {
+		PropertyRef struct {
+			Name string `xml:"Name,attr"`
+		} `xml:"PropertyRef"`
+	} `xml:"Key"`
+	Properties []edmProperty `xml:"Property"`
+}
+
+type edmProperty struct {
+	Name     string `xml:"Name,attr"`
+	Type     string `xml:"Type,attr"`
+	Nullable string `xml:"Nullable,attr,omitempty"`
+}
+
+type edmContainer struct {
+	Name       string         `xml:"Name,attr"`
+	EntitySets []edmEntitySet `xml:"EntitySet"`
+}
+
+type edmEntitySet struct {
+	Name       string `xml:"Name,attr"`
+	EntityType string `xml:"EntityType,attr"`
+}
+
+// Metadata renders the CSDL $metadata document for entity types in namespace, each exposed as
+// an entity set of the same name
+func Metadata(namespace string, entityTypes ...EntityType) ([]byte, error) {
+	doc := edmx{XMLNS: "http://docs.oasis-open.org/odata/ns/edmx", Version: "4.0"}
+	schema := &doc.Services.Schema
+	schema.XMLNS = "http://docs.oasis-open.org/odata/ns/edm"
+	schema.Namespace = namespace
+	schema.Container.Name = "Default"
+
+	for _, entityType := range entityTypes {
+		edmType := edmEntityType{Name: entityType.Name}
+		edmType.Key.PropertyRef.Name = entityType.Key
+		for _, property := range entityType.Properties {
+			edmProperty := edmProperty{Name: property.Name, Type: property.Type}
+			if !property.Nullable {
+				edmProperty.Nullable = "false"
+			}
+			edmType.Properties = append(edmType.Properties, edmProperty)
+		}
+
+		schema.EntityTypes = append(schema.EntityTypes, edmType)
+		schema.Container.EntitySets = append(schema.Container.EntitySets, edmEntitySet{
+			Name:       entityType.Name,
+			EntityType: namespace + "." + entityType.Name,
+		})
+	}
+
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
@@ -0,0 +1,76 @@
+package odata
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OrderBy is one $orderby item: a property and its direction
+type OrderBy struct {
+	Property   string
+	Descending bool
+}
+
+// ParseOrderBy parses $orderby, e.g. "ListPrice desc,BedroomsTotal": comma-separated properties,
+// each optionally followed by asc or desc
+func ParseOrderBy(input string) ([]OrderBy, error) {
+	var items []OrderBy
+	for _, part := range strings.Split(input, ",") {
+		words := strings.Fields(part)
+		switch {
+		case len(words) == 0:
+			return nil, fmt.Errorf("$orderby has an empty item")
+		case len(words) > 2:
+			return nil, fmt.Errorf("$orderby item %q must be a property optionally followed by asc or desc", strings.TrimSpace(part))
+		}
+
+		item := OrderBy{Property: words[0]}
+		if len(words) == 2 {
+			switch words[1] {
+			case "asc":
+			case "desc":
+				item.Descending = true
+			default:
+				return nil, fmt.Errorf("$orderby direction must be asc or desc, got %q", words[1])
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// ParseSelect parses $select into property names; "*" or an empty value selects everything and
+// returns nil
+func ParseSelect(input string) []string {
+	var properties []string
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		if part == "*" {
+			return nil
+		}
+		if part != "" {
+			properties = append(properties, part)
+		}
+	}
+	return properties
+}
+
+// Collection is an OData JSON response carrying a page of entities
+type Collection struct {
+	Context  string           `json:"@odata.context"`
+	Count    *int64           `json:"@odata.count,omitempty"`
+	Value    []map[string]any `json:"value"`
+	NextLink string           `json:"@odata.nextLink,omitempty"`
+}
+
+// ErrorResponse is the OData JSON error format
+type ErrorResponse struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail describes an OData error; Target names the query option at fault
+type ErrorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Target  string `json:"target,omitempty"`
+}
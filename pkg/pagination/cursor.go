@@ -0,0 +1,70 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"housing-api/internal/models"
+)
+
+// ErrInvalidCursor is returned for cursors that are malformed, tampered with or issued for a
+// different sort order
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// Cursor is the decoded form of an opaque pagination cursor: the sort order it was issued for
+// and the position of the last listing already returned
+type Cursor struct {
+	Sort string `json:"s"`
+	models.SortPosition
+}
+
+// CursorCodec encodes and verifies HMAC-signed cursors
+type CursorCodec struct {
+	key []byte
+}
+
+// NewCursorCodec creates a codec signing cursors with secret
+func NewCursorCodec(secret string) *CursorCodec {
+	key := sha256.Sum256([]byte("pagination-cursor:" + secret))
+	return &CursorCodec{key: key[:]}
+}
+
+// Encode returns the opaque, URL-safe form of cursor
+func (c *CursorCodec) Encode(cursor Cursor) string {
+	payload, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(c.sign(payload))
+}
+
+// Decode verifies and decodes a cursor produced by Encode for the given sort order
+func (c *CursorCodec) Decode(token, sort string) (Cursor, error) {
+	encodedPayload, encodedSignature, found := strings.Cut(token, ".")
+	if !found {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, c.sign(payload)) {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(payload, &cursor); err != nil || cursor.Sort != sort {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return cursor, nil
+}
+
+// sign returns a truncated HMAC-SHA256 of payload; 128 bits is plenty to prevent forgery
+func (c *CursorCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write(payload)
+	return mac.Sum(nil)[:16]
+}
@@ -3,6 +3,9 @@ package pagination
 import (
 	"housing-api/internal/models"
 	"math"
+	"net/url"
+	"strconv"
+	"strings"
 )
 
 // CalculateMetadata calculates pagination metadata
@@ -15,4 +18,71 @@ func CalculateMetadata(page, limit int, total int64) models.MetaInfo {
 		Total:      total,
 		TotalPages: totalPages,
 	}
-}
\ No newline at end of file
+}
+
+// BuildLinks returns self/first/prev/next/last URLs for meta, keeping every other query
+// parameter (filters, format, ...) of the request. A page beyond the end links prev to the last page.
+// Cursor pages (no page number) link only to self, first and the next cursor.
+func BuildLinks(baseURL string, query url.Values, meta models.MetaInfo) *models.PaginationLinks {
+	lastPage := meta.TotalPages
+	if lastPage < 1 {
+		lastPage = 1
+	}
+
+	linkURL := func(param, value string) string {
+		q := url.Values{}
+		for key, values := range query {
+			q[key] = values
+		}
+		q.Del("page")
+		q.Del("cursor")
+		q.Set(param, value)
+		q.Set("limit", strconv.Itoa(meta.Limit))
+		return baseURL + "?" + q.Encode()
+	}
+	pageURL := func(page int) string {
+		return linkURL("page", strconv.Itoa(page))
+	}
+
+	if meta.Page == 0 {
+		links := &models.PaginationLinks{
+			Self:  linkURL("cursor", query.Get("cursor")),
+			First: pageURL(1),
+		}
+		if meta.NextCursor != "" {
+			links.Next = linkURL("cursor", meta.NextCursor)
+		}
+		return links
+	}
+
+	links := &models.PaginationLinks{
+		Self:  pageURL(meta.Page),
+		First: pageURL(1),
+		Last:  pageURL(lastPage),
+	}
+	if meta.Page > 1 {
+		links.Prev = pageURL(min(meta.Page-1, lastPage))
+	}
+	if meta.Page < lastPage {
+		links.Next = pageURL(meta.Page + 1)
+	}
+
+	return links
+}
+
+// LinkHeader formats links as an RFC 8288 Link header value
+func LinkHeader(links *models.PaginationLinks) string {
+	var parts []string
+	for _, link := range []struct{ rel, href string }{
+		{"self", links.Self},
+		{"first", links.First},
+		{"prev", links.Prev},
+		{"next", links.Next},
+		{"last", links.Last},
+	} {
+		if link.href != "" {
+			parts = append(parts, "<"+link.href+`>; rel="`+link.rel+`"`)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,293 @@
+package response
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"housing-api/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Supported response formats, selectable with ?format= or the Accept header
+const (
+	FormatJSON    = "json"
+	FormatCSV     = "csv"
+	FormatXML     = "xml"
+	FormatMsgPack = "msgpack"
+)
+
+// FormatQueryParam overrides Accept-based negotiation, e.g. ?format=csv
+const FormatQueryParam = "format"
+
+// mediaTypes maps the media types we can produce to formats, in order of preference
+var mediaTypes = []struct {
+	mediaType string
+	format    string
+}{
+	{fiber.MIMEApplicationJSON, FormatJSON},
+	{"text/csv", FormatCSV},
+	{fiber.MIMEApplicationXML, FormatXML},
+	{fiber.MIMETextXML, FormatXML},
+	{"application/msgpack", FormatMsgPack},
+	{"application/x-msgpack", FormatMsgPack},
+	{"application/vnd.msgpack", FormatMsgPack},
+}
+
+// contentTypes is the Content-Type sent for each format
+var contentTypes = map[string]string{
+	FormatJSON:    fiber.MIMEApplicationJSONCharsetUTF8,
+	FormatCSV:     "text/csv; charset=utf-8",
+	FormatXML:     fiber.MIMEApplicationXMLCharsetUTF8,
+	FormatMsgPack: "application/msgpack",
+}
+
+// CSVRecorder is implemented by items that can be exported as CSV rows
+type CSVRecorder interface {
+	CSVHeader() []string
+	CSVRecord() []string
+}
+
+// errNotTabular reports data that has no CSV representation
+var errNotTabular = errors.New("CSV is only available for listing results")
+
+// write sends body with status in the format negotiated for the request
+func write(c *fiber.Ctx, status int, body models.APIResponse) error {
+	c.Vary(fiber.HeaderAccept)
+
+	format, err := negotiate(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.APIResponse{
+			Success: false,
+			Error:   newErrorInfo(c, fiber.StatusBadRequest, "Unsupported response format", err),
+		})
+	}
+
+	var payload []byte
+	switch format {
+	case FormatCSV:
+		payload, err = encodeCSV(c, body)
+		if errors.Is(err, errNotTabular) {
+			return c.Status(fiber.StatusNotAcceptable).JSON(models.APIResponse{
+				Success: false,
+				Error:   newErrorInfo(c, fiber.StatusNotAcceptable, "Response format not available", err),
+			})
+		}
+	case FormatXML:
+		payload, err = encodeXML(body)
+	case FormatMsgPack:
+		payload, err = encodeMsgPack(body)
+	default:
+		return c.Status(status).JSON(body)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode %s response: %w", format, err)
+	}
+
+	c.Set(fiber.HeaderContentType, contentTypes[format])
+	return c.Status(status).Send(payload)
+}
+
+// negotiate picks the response format from ?format=, falling back to Accept and then JSON
+func negotiate(c *fiber.Ctx) (string, error) {
+	if format := strings.ToLower(c.Query(FormatQueryParam)); format != "" {
+		if _, ok := contentTypes[format]; !ok {
+			return "", fmt.Errorf("format must be one of json, csv, xml, msgpack, got %q", format)
+		}
+		return format, nil
+	}
+
+	offers := make([]string, len(mediaTypes))
+	for i, mt := range mediaTypes {
+		offers[i] = mt.mediaType
+	}
+	accepted := c.Accepts(offers...)
+	for _, mt := range mediaTypes {
+		if mt.mediaType == accepted {
+			return mt.format, nil
+		}
+	}
+	return FormatJSON, nil
+}
+
+// encodeCSV writes listing rows; pagination metadata goes in X-Total-Count/X-Page/X-Limit/X-Total-Pages
+// headers and errors become a single-row table with the envelope's error fields
+func encodeCSV(c *fiber.Ctx, body models.APIResponse) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if body.Error != nil {
+		_ = w.Write([]string{"success", "code", "message", "details", "request_id"})
+		_ = w.Write(escapeFormulas([]string{"false", strconv.Itoa(body.Error.Code), body.Error.Message, body.Error.Details, body.Error.RequestID}))
+		w.Flush()
+		return buf.Bytes(), w.Error()
+	}
+
+	var items []interface{}
+	switch data := body.Data.(type) {
+	case *models.PaginatedResponse:
+		items = data.Items
+		setMetaHeaders(c, data.Meta)
+	case models.PaginatedResponse:
+		items = data.Items
+		setMetaHeaders(c, data.Meta)
+	case CSVRecorder:
+		items = []interface{}{data}
+	default:
+		return nil, errNotTabular
+	}
+
+	// An empty page still gets the listing columns, so clients can tell it from a broken response
+	header := models.Listing{}.CSVHeader()
+	if len(items) > 0 {
+		if record, ok := items[0].(CSVRecorder); ok {
+			header = record.CSVHeader()
+		}
+	}
+	_ = w.Write(header)
+
+	for _, item := range items {
+		record, ok := item.(CSVRecorder)
+		if !ok {
+			return nil, errNotTabular
+		}
+		_ = w.Write(escapeFormulas(record.CSVRecord()))
+	}
+	w.Flush()
+
+	return buf.Bytes(), w.Error()
+}
+
+// escapeFormulas prefixes cells that spreadsheets would evaluate as formulas with a quote, so
+// listing text can't run as a formula when an export is opened
+func escapeFormulas(record []string) []string {
+	for i, cell := range record {
+		if cell != "" && strings.ContainsRune("=+-@", rune(cell[0])) {
+			record[i] = "'" + cell
+		}
+	}
+	return record
+}
+
+// setMetaHeaders exposes pagination metadata for formats without an envelope
+func setMetaHeaders(c *fiber.Ctx, meta models.MetaInfo) {
+	c.Set("X-Total-Count", strconv.FormatInt(meta.Total, 10))
+	c.Set("X-Page", strconv.Itoa(meta.Page))
+	c.Set("X-Limit", strconv.Itoa(meta.Limit))
+	c.Set("X-Total-Pages", strconv.Itoa(meta.TotalPages))
+}
+
+// encodeMsgPack encodes the envelope using the same field names as JSON
+func encodeMsgPack(body models.APIResponse) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(body); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeXML renders the envelope as XML by streaming its JSON form, so field names, order and
+// omitted fields match the JSON response. Arrays become repeated <item> elements and object
+// keys that are not valid XML names become <entry key="...">.
+func encodeXML(body models.APIResponse) ([]byte, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	if err := writeXMLValue(enc, dec, xml.StartElement{Name: xml.Name{Local: "response"}}); err != nil {
+		return nil, err
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeXMLValue writes the next JSON value from dec as the element start
+func writeXMLValue(enc *xml.Encoder, dec *json.Decoder, start xml.StartElement) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			for dec.More() {
+				keyToken, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				if err := writeXMLValue(enc, dec, xmlElement(keyToken.(string))); err != nil {
+					return err
+				}
+			}
+		case '[':
+			for dec.More() {
+				if err := writeXMLValue(enc, dec, xml.StartElement{Name: xml.Name{Local: "item"}}); err != nil {
+					return err
+				}
+			}
+		}
+		// Consume the closing delimiter
+		if _, err := dec.Token(); err != nil && err != io.EOF {
+			return err
+		}
+	case nil:
+	default:
+		if err := enc.EncodeToken(xml.CharData(fmt.Sprint(t))); err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+// xmlElement names an element after key, or uses <entry key="..."> when key is not a valid XML name
+func xmlElement(key string) xml.StartElement {
+	if validXMLName(key) {
+		return xml.StartElement{Name: xml.Name{Local: key}}
+	}
+	return xml.StartElement{
+		Name: xml.Name{Local: "entry"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: key}},
+	}
+}
+
+// validXMLName reports whether key can be used as an element name as-is (a conservative ASCII subset)
+func validXMLName(key string) bool {
+	if key == "" || strings.HasPrefix(strings.ToLower(key), "xml") {
+		return false
+	}
+	for i, r := range key {
+		switch {
+		case r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+		case i > 0 && (r == '-' || r == '.' || (r >= '0' && r <= '9')):
+		default:
+			return false
+		}
+	}
+	return true
+}
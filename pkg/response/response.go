@@ -2,12 +2,13 @@ package response
 
 import (
 	"housing-api/internal/models"
+	"housing-api/pkg/logger"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 func Success(c *fiber.Ctx, message string, data interface{}) error {
-	return c.JSON(models.APIResponse{
+	return write(c, fiber.StatusOK, models.APIResponse{
 		Success: true,
 		Message: message,
 		Data:    data,
@@ -15,98 +16,82 @@ func Success(c *fiber.Ctx, message string, data interface{}) error {
 }
 
 func Created(c *fiber.Ctx, message string, data interface{}) error {
-	return c.Status(fiber.StatusCreated).JSON(models.APIResponse{
+	return write(c, fiber.StatusCreated, models.APIResponse{
 		Success: true,
 		Message: message,
 		Data:    data,
 	})
 }
 
-func BadRequest(c *fiber.Ctx, message string, err error) error {
-	errorInfo := &models.ErrorInfo{
-		Code:    fiber.StatusBadRequest,
-		Message: message,
-	}
-	if err != nil {
-		errorInfo.Details = err.Error()
-	}
-
-	return c.Status(fiber.StatusBadRequest).JSON(models.APIResponse{
+// Error writes an error response with the given status code
+func Error(c *fiber.Ctx, code int, message string, err error) error {
+	return write(c, code, models.APIResponse{
 		Success: false,
-		Error:   errorInfo,
+		Error:   newErrorInfo(c, code, message, err),
 	})
 }
 
+func BadRequest(c *fiber.Ctx, message string, err error) error {
+	return Error(c, fiber.StatusBadRequest, message, err)
+}
+
 func Unauthorized(c *fiber.Ctx, message string, err error) error {
-	errorInfo := &models.ErrorInfo{
-		Code:    fiber.StatusUnauthorized,
-		Message: message,
-	}
-	if err != nil {
-		errorInfo.Details = err.Error()
-	}
+	return Error(c, fiber.StatusUnauthorized, message, err)
+}
 
-	return c.Status(fiber.StatusUnauthorized).JSON(models.APIResponse{
-		Success: false,
-		Error:   errorInfo,
-	})
+func Forbidden(c *fiber.Ctx, message string, err error) error {
+	return Error(c, fiber.StatusForbidden, message, err)
 }
 
 func NotFound(c *fiber.Ctx, message string, err error) error {
-	errorInfo := &models.ErrorInfo{
-		Code:    fiber.StatusNotFound,
-		Message: message,
-	}
-	if err != nil {
-		errorInfo.Details = err.Error()
-	}
-
-	return c.Status(fiber.StatusNotFound).JSON(models.APIResponse{
-		Success: false,
-		Error:   errorInfo,
-	})
+	return Error(c, fiber.StatusNotFound, message, err)
 }
 
 func Conflict(c *fiber.Ctx, message string, err error) error {
-	errorInfo := &models.ErrorInfo{
-		Code:    fiber.StatusConflict,
-		Message: message,
-	}
-	if err != nil {
-		errorInfo.Details = err.Error()
-	}
+	return Error(c, fiber.StatusConflict, message, err)
+}
 
-	return c.Status(fiber.StatusConflict).JSON(models.APIResponse{
-		Success: false,
-		Error:   errorInfo,
-	})
+func TooManyRequests(c *fiber.Ctx, message string, err error) error {
+	return Error(c, fiber.StatusTooManyRequests, message, err)
+}
+
+func UnprocessableEntity(c *fiber.Ctx, message string, err error) error {
+	return Error(c, fiber.StatusUnprocessableEntity, message, err)
 }
 
 func InternalServerError(c *fiber.Ctx, message string, err error) error {
-	errorInfo := &models.ErrorInfo{
-		Code:    fiber.StatusInternalServerError,
-		Message: message,
-	}
-	if err != nil {
-		errorInfo.Details = err.Error()
-	}
+	return Error(c, fiber.StatusInternalServerError, message, err)
+}
 
-	return c.Status(fiber.StatusInternalServerError).JSON(models.APIResponse{
+// ServiceUnavailable writes a 503 response that still carries a payload, e.g. failing health checks
+func ServiceUnavailable(c *fiber.Ctx, message string, data interface{}) error {
+	return write(c, fiber.StatusServiceUnavailable, models.APIResponse{
 		Success: false,
-		Error:   errorInfo,
+		Error:   newErrorInfo(c, fiber.StatusServiceUnavailable, message, nil),
+		Data:    data,
 	})
 }
 
 func ValidationError(c *fiber.Ctx, message string, errors []models.ValidationError) error {
-	return c.Status(fiber.StatusUnprocessableEntity).JSON(models.APIResponse{
+	return write(c, fiber.StatusUnprocessableEntity, models.APIResponse{
 		Success: false,
-		Error: &models.ErrorInfo{
-			Code:    fiber.StatusUnprocessableEntity,
-			Message: message,
-		},
+		Error:   newErrorInfo(c, fiber.StatusUnprocessableEntity, message, nil),
 		Data: models.ValidationErrorResponse{
 			Message: message,
 			Errors:  errors,
 		},
 	})
 }
+
+// newErrorInfo builds the error payload, tagging it with the request ID so clients can quote it
+func newErrorInfo(c *fiber.Ctx, code int, message string, err error) *models.ErrorInfo {
+	errorInfo := &models.ErrorInfo{
+		Code:      code,
+		Message:   message,
+		RequestID: logger.RequestIDFromContext(c.UserContext()),
+	}
+	if err != nil {
+		errorInfo.Details = err.Error()
+	}
+	return errorInfo
+}
@@ -0,0 +1,80 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Analyze splits text into search terms: case- and diacritic-folded words and numbers, stemmed
+// so that plural and inflected forms meet ("Duplexes" and "duplex", "Serviced" and "service")
+func Analyze(text string) []string {
+	words := Words(text)
+
+	terms := make([]string, len(words))
+	for i, word := range words {
+		terms[i] = Stem(word)
+	}
+	return terms
+}
+
+// Words splits text into folded words and numbers, without stemming
+func Words(text string) []string {
+	return strings.FieldsFunc(Fold(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Fold lowercases text and strips diacritics ("Ìkẹjà" becomes "ikeja")
+func Fold(text string) string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), text)
+	if err != nil {
+		folded = text
+	}
+	return strings.ToLower(folded)
+}
+
+// Stem reduces an English word to a crude stem by stripping common suffixes. It is deliberately
+// simple: both the indexed text and the query go through it, so stems only need to agree.
+func Stem(word string) string {
+	if len(word) <= 3 || !isAlpha(word) {
+		return word
+	}
+
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		word = word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "sses"), strings.HasSuffix(word, "ches"),
+		strings.HasSuffix(word, "shes"), strings.HasSuffix(word, "xes"), strings.HasSuffix(word, "zes"):
+		word = word[:len(word)-2]
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") &&
+		!strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is"):
+		word = word[:len(word)-1]
+	}
+
+	switch {
+	case strings.HasSuffix(word, "ing") && len(word) >= 7:
+		word = word[:len(word)-3]
+	case strings.HasSuffix(word, "ed") && len(word) >= 6:
+		word = word[:len(word)-2]
+	}
+
+	// "service"/"serviced" and "house"/"houses" meet once a final e is dropped
+	if strings.HasSuffix(word, "e") && len(word) >= 5 {
+		word = word[:len(word)-1]
+	}
+	return word
+}
+
+// isAlpha reports whether word contains only letters
+func isAlpha(word string) bool {
+	for _, r := range word {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package search
+
+import (
+	"sort"
+	"strings"
+)
+
+// Completion is an autocomplete candidate: the text to complete to, what kind of value it is and
+// how many documents carry it
+type Completion struct {
+	Text  string `json:"text"`
+	Kind  string `json:"type"`
+	Count int    `json:"count"`
+}
+
+// prefixKey is one searchable suffix of a completion's folded words; leading keys start at the
+// first word
+type prefixKey struct {
+	key     string
+	entry   int
+	leading bool
+}
+
+// PrefixIndex answers prefix queries over completions with a sorted key list and binary search.
+// Every word of a completion starts a key, so "phase" completes to "Lekki Phase 1".
+type PrefixIndex struct {
+	entries []Completion
+	keys    []prefixKey
+	kinds   map[string]int
+}
+
+// NewPrefixIndex indexes entries; kinds lists the completion kinds in ranking order for ties
+func NewPrefixIndex(entries []Completion, kinds ...string) *PrefixIndex {
+	idx := &PrefixIndex{entries: entries, kinds: make(map[string]int, len(kinds))}
+	for i, kind := range kinds {
+		idx.kinds[kind] = i
+	}
+
+	for i, entry := range entries {
+		words := Words(entry.Text)
+		for w := range words {
+			idx.keys = append(idx.keys, prefixKey{key: strings.Join(words[w:], " "), entry: i, leading: w == 0})
+		}
+	}
+
+	sort.Slice(idx.keys, func(i, j int) bool {
+		return idx.keys[i].key < idx.keys[j].key
+	})
+	return idx
+}
+
+// Complete returns up to limit completions whose text, or a word within it, starts with prefix.
+// Matches from the start of the text rank first, then by count.
+func (idx *PrefixIndex) Complete(prefix string, limit int) []Completion {
+	query := strings.Join(Words(prefix), " ")
+	if query == "" || limit <= 0 {
+		return []Completion{}
+	}
+	// A trailing space means the last word is complete: "lekki " shouldn't match "lekkis"
+	wholeWord := strings.HasSuffix(prefix, " ")
+
+	leading := make(map[int]bool)
+	start := sort.Search(len(idx.keys), func(i int) bool {
+		return idx.keys[i].key >= query
+	})
+	for i := start; i < len(idx.keys) && strings.HasPrefix(idx.keys[i].key, query); i++ {
+		key := idx.keys[i]
+		if wholeWord && !strings.HasPrefix(key.key+" ", query+" ") {
+			continue
+		}
+		leading[key.entry] = leading[key.entry] || key.leading
+	}
+
+	matches := make([]int, 0, len(leading))
+	for entry := range leading {
+		matches = append(matches, entry)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := idx.entries[matches[i]], idx.entries[matches[j]]
+		switch {
+		case leading[matches[i]] != leading[matches[j]]:
+			return leading[matches[i]]
+		case a.Count != b.Count:
+			return a.Count > b.Count
+		case idx.kinds[a.Kind] != idx.kinds[b.Kind]:
+			return idx.kinds[a.Kind] < idx.kinds[b.Kind]
+		case len(a.Text) != len(b.Text):
+			return len(a.Text) < len(b.Text)
+		}
+		return a.Text < b.Text
+	})
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	completions := make([]Completion, len(matches))
+	for i, entry := range matches {
+		completions[i] = idx.entries[entry]
+	}
+	return completions
+}
@@ -0,0 +1,88 @@
+package search
+
+import "strings"
+
+// MaxEdits is the number of typos tolerated in a word of the given length: none for very short
+// words, where a single edit usually means a different word, one up to five letters, two beyond
+func MaxEdits(word string) int {
+	switch n := len([]rune(word)); {
+	case n <= 3:
+		return 0
+	case n <= 5:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// EditDistance returns the optimal-string-alignment distance between a and b (insertions,
+// deletions, substitutions and adjacent transpositions), or max+1 once it exceeds max
+func EditDistance(a, b string, max int) int {
+	ra, rb := []rune(a), []rune(b)
+	if diff := len(ra) - len(rb); diff > max || -diff > max {
+		return max + 1
+	}
+
+	// Three rolling rows: two back (for transpositions), previous and current
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, cur[j])
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+
+	if prev[len(rb)] > max {
+		return max + 1
+	}
+	return prev[len(rb)]
+}
+
+// FuzzyContains reports whether text contains query, tolerating typos: either a plain
+// case- and diacritic-insensitive substring match, or every query word within MaxEdits of some
+// word of text ("Victoria Iland" matches "Victoria Island, Lagos")
+func FuzzyContains(text, query string) bool {
+	foldedText, foldedQuery := Fold(text), Fold(query)
+	if strings.Contains(foldedText, strings.TrimSpace(foldedQuery)) {
+		return true
+	}
+
+	textWords := Words(foldedText)
+	queryWords := Words(foldedQuery)
+	if len(queryWords) == 0 {
+		return false
+	}
+
+	for _, queryWord := range queryWords {
+		found := false
+		for _, textWord := range textWords {
+			if maxEdits := MaxEdits(queryWord); EditDistance(queryWord, textWord, maxEdits) <= maxEdits {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,239 @@
+package search
+
+import (
+	"math"
+	"strings"
+)
+
+// BM25 parameters: k1 controls term-frequency saturation, b how strongly field length normalizes
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// fuzzyPenalty scales the score of a typo-corrected term match per edit
+const fuzzyPenalty = 0.5
+
+// Field is an indexed text field and its weight in scoring
+type Field struct {
+	Name   string
+	Weight float64
+}
+
+// Document is a unit of search: an ID and its field values, in the index's field order
+type Document struct {
+	ID     int
+	Fields []string
+}
+
+// posting records how often a term occurs in each field of one document
+type posting struct {
+	doc   int
+	freqs []int
+}
+
+// Index is an immutable inverted index scoring documents with BM25F (BM25 over weighted,
+// length-normalized fields). Build a new index when the documents change.
+type Index struct {
+	fields   []Field
+	postings map[string][]posting
+	lengths  map[int][]int
+	average  []float64
+
+	// surface maps each term to the first word it was indexed from, for readable suggestions;
+	// byLength groups terms by rune count so fuzzy lookups only compare plausible candidates
+	surface  map[string]string
+	byLength map[int][]string
+}
+
+// NewIndex analyzes and indexes docs
+func NewIndex(fields []Field, docs []Document) *Index {
+	idx := &Index{
+		fields:   fields,
+		postings: make(map[string][]posting),
+		lengths:  make(map[int][]int, len(docs)),
+		average:  make([]float64, len(fields)),
+		surface:  make(map[string]string),
+		byLength: make(map[int][]string),
+	}
+
+	for _, doc := range docs {
+		lengths := make([]int, len(fields))
+		freqs := make(map[string][]int)
+
+		for f := range fields {
+			if f >= len(doc.Fields) {
+				break
+			}
+			words := Words(doc.Fields[f])
+			lengths[f] = len(words)
+			idx.average[f] += float64(len(words))
+			for _, word := range words {
+				term := Stem(word)
+				if _, ok := idx.surface[term]; !ok {
+					idx.surface[term] = word
+					idx.byLength[len([]rune(term))] = append(idx.byLength[len([]rune(term))], term)
+				}
+				if freqs[term] == nil {
+					freqs[term] = make([]int, len(fields))
+				}
+				freqs[term][f]++
+			}
+		}
+
+		idx.lengths[doc.ID] = lengths
+		for term, counts := range freqs {
+			idx.postings[term] = append(idx.postings[term], posting{doc: doc.ID, freqs: counts})
+		}
+	}
+
+	if len(docs) > 0 {
+		for f := range idx.average {
+			idx.average[f] /= float64(len(docs))
+		}
+	}
+	return idx
+}
+
+// Len returns the number of indexed documents
+func (idx *Index) Len() int {
+	return len(idx.lengths)
+}
+
+// Search returns the BM25F score of every document containing all of the query's terms. A term
+// that isn't indexed matches the indexed terms within MaxEdits of it instead, at a reduced score.
+func (idx *Index) Search(query string) map[int]float64 {
+	scores := make(map[int]float64)
+
+	terms := unique(Analyze(query))
+	for i, term := range terms {
+		matched := make(map[int]float64)
+		for variant, distance := range idx.variants(term) {
+			weight := math.Pow(fuzzyPenalty, float64(distance))
+			for _, p := range idx.postings[variant] {
+				if _, ok := scores[p.doc]; i > 0 && !ok {
+					continue
+				}
+				// A document matching several variants counts its best one
+				score := scores[p.doc] + weight*idx.score(variant, p)
+				if score > matched[p.doc] {
+					matched[p.doc] = score
+				}
+			}
+		}
+		scores = matched
+		if len(scores) == 0 {
+			break
+		}
+	}
+
+	return scores
+}
+
+// SearchAny runs Search for each query and merges the results, keeping each document's best score;
+// used to search all synonym rewrites of a query at once
+func (idx *Index) SearchAny(queries []string) map[int]float64 {
+	scores := make(map[int]float64)
+	for _, query := range queries {
+		for doc, score := range idx.Search(query) {
+			if score > scores[doc] {
+				scores[doc] = score
+			}
+		}
+	}
+	return scores
+}
+
+// Suggest returns a corrected form of a query that has no matches ("did you mean"), replacing
+// each unknown word with the closest indexed word, allowing one more typo than Search does.
+// It returns "" when no correction finds any documents.
+func (idx *Index) Suggest(query string) string {
+	words := Words(query)
+	corrected := make([]string, len(words))
+	changed := false
+
+	for i, word := range words {
+		corrected[i] = word
+		term := Stem(word)
+		if _, ok := idx.postings[term]; ok {
+			continue
+		}
+
+		best, bestDistance := "", MaxEdits(term)+2
+		for candidate, distance := range idx.fuzzy(term, MaxEdits(term)+1) {
+			if distance < bestDistance ||
+				(distance == bestDistance && len(idx.postings[candidate]) > len(idx.postings[best])) {
+				best, bestDistance = candidate, distance
+			}
+		}
+		if best != "" {
+			corrected[i] = idx.surface[best]
+			changed = true
+		}
+	}
+
+	suggestion := strings.Join(corrected, " ")
+	if !changed || len(idx.Search(suggestion)) == 0 {
+		return ""
+	}
+	return suggestion
+}
+
+// variants returns the indexed terms a query term matches, with their edit distance: the term
+// itself when indexed, otherwise its fuzzy matches
+func (idx *Index) variants(term string) map[string]int {
+	if _, ok := idx.postings[term]; ok {
+		return map[string]int{term: 0}
+	}
+	return idx.fuzzy(term, MaxEdits(term))
+}
+
+// fuzzy returns the indexed terms within maxEdits of term, with their distance
+func (idx *Index) fuzzy(term string, maxEdits int) map[string]int {
+	matches := make(map[string]int)
+	if maxEdits == 0 {
+		return matches
+	}
+
+	n := len([]rune(term))
+	for length := n - maxEdits; length <= n+maxEdits; length++ {
+		for _, candidate := range idx.byLength[length] {
+			if distance := EditDistance(term, candidate, maxEdits); distance <= maxEdits {
+				matches[candidate] = distance
+			}
+		}
+	}
+	return matches
+}
+
+// score is term's BM25F contribution for one document
+func (idx *Index) score(term string, p posting) float64 {
+	n := float64(len(idx.lengths))
+	df := float64(len(idx.postings[term]))
+	idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+	var tf float64
+	lengths := idx.lengths[p.doc]
+	for f, field := range idx.fields {
+		if p.freqs[f] == 0 || idx.average[f] == 0 {
+			continue
+		}
+		norm := 1 - b + b*float64(lengths[f])/idx.average[f]
+		tf += field.Weight * float64(p.freqs[f]) / norm
+	}
+
+	return idf * tf * (k1 + 1) / (tf + k1)
+}
+
+// unique drops repeated terms, keeping the first occurrence
+func unique(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	result := terms[:0]
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			result = append(result, term)
+		}
+	}
+	return result
+}
@@ -0,0 +1,124 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxVariants caps how many rewrites of one query Expand produces
+const maxVariants = 16
+
+// Dictionary is the editable synonym file: groups of interchangeable terms, and one-way
+// abbreviations that expand to their full form
+type Dictionary struct {
+	Synonyms      [][]string        `json:"synonyms"`
+	Abbreviations map[string]string `json:"abbreviations"`
+}
+
+// Validate reports every malformed group or abbreviation
+func (d Dictionary) Validate() error {
+	var errs []error
+	for i, group := range d.Synonyms {
+		if len(group) < 2 {
+			errs = append(errs, fmt.Errorf("synonyms[%d]: a group needs at least two terms", i))
+		}
+		for _, term := range group {
+			if len(Words(term)) == 0 {
+				errs = append(errs, fmt.Errorf("synonyms[%d]: empty term %q", i, term))
+			}
+		}
+	}
+	for abbreviation, expansion := range d.Abbreviations {
+		if len(Words(abbreviation)) == 0 || len(Words(expansion)) == 0 {
+			errs = append(errs, fmt.Errorf("abbreviations: %q -> %q must both contain words", abbreviation, expansion))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Synonyms rewrites text using a Dictionary. A nil *Synonyms expands nothing.
+type Synonyms struct {
+	dictionary   Dictionary
+	alternatives map[string][]string
+	maxWords     int
+}
+
+// NewSynonyms prepares d for expansion; phrases are matched on folded words
+func NewSynonyms(d Dictionary) *Synonyms {
+	s := &Synonyms{dictionary: d, alternatives: make(map[string][]string)}
+
+	add := func(from, to string) {
+		from, to = phrase(from), phrase(to)
+		if from == to {
+			return
+		}
+		for _, existing := range s.alternatives[from] {
+			if existing == to {
+				return
+			}
+		}
+		s.alternatives[from] = append(s.alternatives[from], to)
+		s.maxWords = max(s.maxWords, len(strings.Fields(from)))
+	}
+
+	for _, group := range d.Synonyms {
+		for _, from := range group {
+			for _, to := range group {
+				add(from, to)
+			}
+		}
+	}
+	for abbreviation, expansion := range d.Abbreviations {
+		add(abbreviation, expansion)
+	}
+	return s
+}
+
+// Dictionary returns the dictionary the synonyms were built from
+func (s *Synonyms) Dictionary() Dictionary {
+	if s == nil {
+		return Dictionary{Synonyms: [][]string{}, Abbreviations: map[string]string{}}
+	}
+	return s.dictionary
+}
+
+// Expand returns text (folded) followed by its rewrites, replacing each known phrase with its
+// alternatives; the longest phrase wins where entries overlap ("self con" before "self")
+func (s *Synonyms) Expand(text string) []string {
+	words := Words(text)
+	original := strings.Join(words, " ")
+	if s == nil || len(s.alternatives) == 0 {
+		return []string{original}
+	}
+
+	variants := []string{""}
+	for i := 0; i < len(words); {
+		options, length := []string{words[i]}, 1
+		for k := min(s.maxWords, len(words)-i); k > 0; k-- {
+			candidate := strings.Join(words[i:i+k], " ")
+			if alternatives, ok := s.alternatives[candidate]; ok {
+				options, length = append([]string{candidate}, alternatives...), k
+				break
+			}
+		}
+		i += length
+
+		var next []string
+		for _, variant := range variants {
+			for _, option := range options {
+				if len(next) < maxVariants {
+					next = append(next, strings.TrimSpace(variant+" "+option))
+				}
+			}
+		}
+		variants = next
+	}
+
+	return variants
+}
+
+// phrase normalizes a dictionary entry to folded words separated by single spaces
+func phrase(text string) string {
+	return strings.Join(Words(text), " ")
+}
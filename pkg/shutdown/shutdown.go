@@ -0,0 +1,69 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Hook flushes or releases a resource during shutdown
+type Hook func(ctx context.Context) error
+
+type namedHook struct {
+	name string
+	hook Hook
+}
+
+var (
+	mu    sync.Mutex
+	hooks []namedHook
+	tasks sync.WaitGroup
+)
+
+// Register adds a hook to run at shutdown; hooks run in reverse registration order
+func Register(name string, hook Hook) {
+	mu.Lock()
+	defer mu.Unlock()
+	hooks = append(hooks, namedHook{name: name, hook: hook})
+}
+
+// Go runs fn in a goroutine that shutdown waits for, e.g. a deferred file write
+func Go(fn func()) {
+	tasks.Add(1)
+	go func() {
+		defer tasks.Done()
+		fn()
+	}()
+}
+
+// Run waits for background work started with Go, then runs every registered hook.
+// It gives up when ctx expires and reports all hook failures.
+func Run(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		tasks.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for background work: %w", ctx.Err())
+	}
+
+	mu.Lock()
+	pending := make([]namedHook, len(hooks))
+	copy(pending, hooks)
+	hooks = nil
+	mu.Unlock()
+
+	var errs []error
+	for i := len(pending) - 1; i >= 0; i-- {
+		if err := pending[i].hook(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", pending[i].name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
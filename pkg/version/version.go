@@ -0,0 +1,29 @@
+package version
+
+import "runtime"
+
+// Build metadata, stamped at build time via
+// -ldflags "-X housing-api/pkg/version.Version=... -X housing-api/pkg/version.Commit=... -X housing-api/pkg/version.BuildTime=..."
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
+
+// Info describes the running build
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns the build metadata of the running binary
+func Get() Info {
+	return Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+}
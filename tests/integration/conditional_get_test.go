@@ -0,0 +1,121 @@
+package integration
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"housing-api/internal/config"
+	"housing-api/pkg/jwt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditionalGet_ETagAndCacheControl(t *testing.T) {
+	app := setupTestApp()
+
+	req := httptest.NewRequest("GET", "/api/v1/listings?page=1&limit=5", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotEmpty(t, resp.Header.Get("ETag"))
+	assert.NotEmpty(t, resp.Header.Get("Last-Modified"))
+	assert.Equal(t, "public, max-age=60", resp.Header.Get("Cache-Control"))
+}
+
+func TestConditionalGet_IfNoneMatchReturnsNotModified(t *testing.T) {
+	app := setupTestApp()
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/listings/1", nil))
+	require.NoError(t, err)
+	etag := resp.Header.Get("ETag")
+	require.NotEmpty(t, etag)
+
+	req := httptest.NewRequest("GET", "/api/v1/listings/1", nil)
+	req.Header.Set("If-None-Match", etag)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+	assert.Equal(t, etag, resp.Header.Get("ETag"))
+	body, _ := io.ReadAll(resp.Body)
+	assert.Empty(t, body)
+}
+
+func TestConditionalGet_DifferentPayloadsHaveDifferentETags(t *testing.T) {
+	app := setupTestApp()
+
+	first, err := app.Test(httptest.NewRequest("GET", "/api/v1/listings/1", nil))
+	require.NoError(t, err)
+	second, err := app.Test(httptest.NewRequest("GET", "/api/v1/listings/2", nil))
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first.Header.Get("ETag"), second.Header.Get("ETag"))
+
+	req := httptest.NewRequest("GET", "/api/v1/listings/2", nil)
+	req.Header.Set("If-None-Match", first.Header.Get("ETag"))
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestConditionalGet_IfModifiedSince(t *testing.T) {
+	app := setupTestApp()
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/listings/filters", nil))
+	require.NoError(t, err)
+	lastModified := resp.Header.Get("Last-Modified")
+	require.NotEmpty(t, lastModified)
+
+	req := httptest.NewRequest("GET", "/api/v1/listings/filters", nil)
+	req.Header.Set("If-Modified-Since", lastModified)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+}
+
+func TestConditionalGet_StatsRouteNotShadowedByID(t *testing.T) {
+	app := setupTestApp()
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/listings/stats", nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestConditionalGet_ReloadAdvancesLastModified(t *testing.T) {
+	t.Setenv("ADMIN_EMAILS", "admin@worksquare.com")
+	cfg, _ := config.Load()
+	token, err := jwt.GenerateToken(99, "admin@worksquare.com", cfg.JWTSecret, time.Hour)
+	require.NoError(t, err)
+	app := setupTestApp()
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/listings/filters", nil))
+	require.NoError(t, err)
+	lastModified := resp.Header.Get("Last-Modified")
+	require.NotEmpty(t, lastModified)
+
+	// Reloading the same file is still a change: cached copies must be revalidated
+	req := httptest.NewRequest("POST", "/api/v1/admin/listings/reload", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	req = httptest.NewRequest("GET", "/api/v1/listings/filters", nil)
+	req.Header.Set("If-Modified-Since", lastModified)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	before, err := http.ParseTime(lastModified)
+	require.NoError(t, err)
+	after, err := http.ParseTime(resp.Header.Get("Last-Modified"))
+	require.NoError(t, err)
+	assert.True(t, after.After(before))
+}
@@ -0,0 +1,127 @@
+package integration
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"housing-api/internal/models"
+	"housing-api/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestContentNegotiation_CSVViaFormatParam(t *testing.T) {
+	app := setupTestApp()
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/listings?limit=3&format=csv", nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/csv")
+	assert.NotEmpty(t, resp.Header.Get("X-Total-Count"))
+
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	assert.Equal(t, []string{"id", "title", "price", "price_numeric"}, records[0][:4])
+}
+
+func TestContentNegotiation_CSVEmptyPageHasHeader(t *testing.T) {
+	app := setupTestApp()
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/listings?city=Nowhereville&format=csv", nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "0", resp.Header.Get("X-Total-Count"))
+
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, []string{"id", "title", "price", "price_numeric"}, records[0][:4])
+}
+
+func TestContentNegotiation_CSVEscapesFormulas(t *testing.T) {
+	app := fiber.New()
+	app.Get("/export", func(c *fiber.Ctx) error {
+		return response.Success(c, "ok", models.Listing{ID: 1, Title: "=HYPERLINK(\"http://evil\")", Location: "@Lekki", Price: "+234"})
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/export?format=csv", nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	record := map[string]string{}
+	for i, column := range records[0] {
+		record[column] = records[1][i]
+	}
+	assert.Equal(t, `'=HYPERLINK("http://evil")`, record["title"])
+	assert.Equal(t, "'@Lekki", record["location"])
+	assert.Equal(t, "'+234", record["price"])
+	assert.Equal(t, "1", record["id"])
+}
+
+func TestContentNegotiation_XMLViaAccept(t *testing.T) {
+	app := setupTestApp()
+
+	req := httptest.NewRequest("GET", "/api/v1/listings/1", nil)
+	req.Header.Set("Accept", "application/xml")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	assert.Contains(t, resp.Header.Get("Content-Type"), "application/xml")
+	assert.Contains(t, resp.Header.Get("Vary"), "Accept")
+
+	var body struct {
+		Success bool `xml:"success"`
+		Data    struct {
+			ID int `xml:"id"`
+		} `xml:"data"`
+	}
+	require.NoError(t, xml.NewDecoder(resp.Body).Decode(&body))
+	assert.True(t, body.Success)
+	assert.Equal(t, 1, body.Data.ID)
+}
+
+func TestContentNegotiation_MessagePackKeepsEnvelope(t *testing.T) {
+	app := setupTestApp()
+
+	req := httptest.NewRequest("GET", "/api/v1/listings?limit=2", nil)
+	req.Header.Set("Accept", "application/msgpack")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, "application/msgpack", resp.Header.Get("Content-Type"))
+
+	var body map[string]interface{}
+	require.NoError(t, msgpack.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, true, body["success"])
+	data := body["data"].(map[string]interface{})
+	assert.Len(t, data["items"], 2)
+}
+
+func TestContentNegotiation_RejectsUnknownFormat(t *testing.T) {
+	app := setupTestApp()
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/listings?format=yaml", nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestContentNegotiation_CSVNotAvailableForMetadata(t *testing.T) {
+	app := setupTestApp()
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/listings/filters?format=csv", nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNotAcceptable, resp.StatusCode)
+}
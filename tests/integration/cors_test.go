@@ -0,0 +1,68 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"housing-api/internal/config"
+	"housing-api/internal/middleware/security"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupCORSTestApp(t *testing.T) *fiber.App {
+	t.Setenv("CORS_ALLOW_ORIGINS", "https://app.worksquare.com,https://*.preview.worksquare.com")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+	t.Setenv("CORS_MAX_AGE", "10m")
+	t.Setenv("SECURITY_REFERRER_POLICY", "strict-origin-when-cross-origin")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+
+	app := fiber.New()
+	app.Use(security.Headers(cfg))
+	app.Use(security.CORS(cfg))
+	app.Get("/ping", func(c *fiber.Ctx) error { return c.SendString("pong") })
+	return app
+}
+
+func TestCORS_CredentialedRequestFromAllowedOrigin(t *testing.T) {
+	app := setupCORSTestApp(t)
+
+	req := httptest.NewRequest("GET", "/ping", nil)
+	req.Header.Set("Origin", "https://app.worksquare.com")
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://app.worksquare.com", resp.Header.Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", resp.Header.Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "strict-origin-when-cross-origin", resp.Header.Get("Referrer-Policy"))
+}
+
+func TestCORS_WildcardSubdomainPreflight(t *testing.T) {
+	app := setupCORSTestApp(t)
+
+	req := httptest.NewRequest("OPTIONS", "/ping", nil)
+	req.Header.Set("Origin", "https://pr-42.preview.worksquare.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "https://pr-42.preview.worksquare.com", resp.Header.Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "600", resp.Header.Get("Access-Control-Max-Age"))
+}
+
+func TestCORS_UnknownOriginNotAllowed(t *testing.T) {
+	app := setupCORSTestApp(t)
+
+	req := httptest.NewRequest("GET", "/ping", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+}
@@ -0,0 +1,43 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorPagination_FollowsNextCursorWithFilters(t *testing.T) {
+	resp, first := getLinkedPage(t, "/api/v1/listings?city=Lagos&limit=2")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NotEmpty(t, first.Data.Meta.NextCursor)
+
+	next, err := url.Parse(first.Data.Meta.Links.Next)
+	require.NoError(t, err)
+	assert.Equal(t, "Lagos", next.Query().Get("city"))
+
+	resp, second := getLinkedPage(t, "/api/v1/listings?city=Lagos&limit=2&cursor="+url.QueryEscape(first.Data.Meta.NextCursor))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NotEmpty(t, second.Data.Items)
+	assert.Greater(t, second.Data.Items[0].ID, first.Data.Items[len(first.Data.Items)-1].ID)
+	assert.Equal(t, first.Data.Meta.Total, second.Data.Meta.Total)
+	assert.Zero(t, second.Data.Meta.Page)
+
+	// The second cursor page matches the second offset page
+	_, paged := getLinkedPage(t, "/api/v1/listings?city=Lagos&limit=2&page=2")
+	assert.Equal(t, paged.Data.Items, second.Data.Items)
+}
+
+func TestCursorPagination_RejectsTamperedCursor(t *testing.T) {
+	app := setupTestApp()
+
+	_, first := getLinkedPage(t, "/api/v1/listings?limit=2")
+	tampered := "x" + first.Data.Meta.NextCursor
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/listings?cursor="+url.QueryEscape(tampered), nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
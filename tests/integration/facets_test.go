@@ -0,0 +1,44 @@
+package integration
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"housing-api/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFacets_ReturnedWithListingsAndSearch(t *testing.T) {
+	app := setupTestApp()
+
+	for _, target := range []string{
+		"/api/v1/listings?city=Lagos&facets=city,property_type,price",
+		"/api/v1/listings/search?q=duplex&facets=all",
+	} {
+		resp, err := app.Test(httptest.NewRequest("GET", target, nil))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode, target)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		var result struct {
+			Data models.PaginatedResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(body, &result))
+		assert.NotEmpty(t, result.Data.Facets[models.FacetCity], target)
+		assert.NotEmpty(t, result.Data.Facets[models.FacetPrice], target)
+	}
+}
+
+func TestFacets_RejectsUnknownFacet(t *testing.T) {
+	app := setupTestApp()
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/listings?facets=colour", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
@@ -0,0 +1,51 @@
+package integration
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"housing-api/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterExpression_FiltersListings(t *testing.T) {
+	expression := `(city:Lagos OR city:Abuja) AND price<=3000000 AND bedrooms>=3`
+	resp, page := getLinkedPage(t, "/api/v1/listings?limit=100&filter="+url.QueryEscape(expression))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NotEmpty(t, page.Data.Items)
+	for _, listing := range page.Data.Items {
+		assert.GreaterOrEqual(t, listing.Bedrooms, 3)
+		assert.LessOrEqual(t, listing.GetPriceNumeric(), 3000000.0)
+	}
+
+	resp, search := getLinkedPage(t, "/api/v1/listings/search?q=duplex&filter="+url.QueryEscape(`title~"pool"`))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NotEmpty(t, search.Data.Items)
+	for _, listing := range search.Data.Items {
+		assert.Contains(t, listing.Title, "Pool")
+	}
+}
+
+func TestFilterExpression_ReportsErrorColumn(t *testing.T) {
+	app := setupTestApp()
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/listings?filter="+url.QueryEscape("bedrooms>=3 AND colour:red"), nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var result struct {
+		Data models.ValidationErrorResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &result))
+	require.Len(t, result.Data.Errors, 1)
+	assert.Equal(t, "filter", result.Data.Errors[0].Field)
+	assert.Contains(t, result.Data.Errors[0].Message, "column 17")
+}
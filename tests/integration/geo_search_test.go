@@ -0,0 +1,66 @@
+package integration
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"housing-api/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeoSearch_RadiusSortedByDistance(t *testing.T) {
+	resp, page := getLinkedPage(t, "/api/v1/listings?near=6.4478,3.4723&radius_km=5&sort=distance")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, page.Data.Items, 6)
+	assert.Equal(t, 5, page.Data.Items[0].ID)
+	assert.Equal(t, 0.0, *page.Data.Items[0].DistanceKm)
+	for i := 1; i < len(page.Data.Items); i++ {
+		assert.GreaterOrEqual(t, *page.Data.Items[i].DistanceKm, *page.Data.Items[i-1].DistanceKm)
+	}
+
+	// Search combines with a bounding box around Ikoyi and Victoria Island
+	resp, page = getLinkedPage(t, "/api/v1/listings/search?q=lagos&min_lat=6.4&max_lat=6.5&min_lng=3.3&max_lng=3.5")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NotEmpty(t, page.Data.Items)
+	for _, listing := range page.Data.Items {
+		require.NotNil(t, listing.Latitude)
+		assert.InDelta(t, 6.45, *listing.Latitude, 0.05)
+		assert.Nil(t, listing.DistanceKm)
+	}
+}
+
+func TestGeoSearch_RejectsInvalidParameters(t *testing.T) {
+	app := setupTestApp()
+
+	for target, field := range map[string]string{
+		"/api/v1/listings?near=lagos":                    "near",
+		"/api/v1/listings?radius_km=5":                   "radius_km",
+		"/api/v1/listings?near=6.4,3.4&radius_km=-1":     "radius_km",
+		"/api/v1/listings?min_lat=7&max_lat=6":           "min_lat",
+		"/api/v1/listings?near=6.4,3.4&radius_km=NaN":    "radius_km",
+		"/api/v1/listings?min_lat=NaN":                   "min_lat",
+		"/api/v1/listings?max_lat=NaN":                   "max_lat",
+		"/api/v1/listings?min_lng=NaN&city=Abuja":        "min_lng",
+		"/api/v1/listings?max_lng=NaN":                   "max_lng",
+		"/api/v1/listings?sort=distance":                 "sort",
+		"/api/v1/listings/search?q=duplex&sort=distance": "sort",
+	} {
+		resp, err := app.Test(httptest.NewRequest("GET", target, nil))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode, target)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		var result struct {
+			Data models.ValidationErrorResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(body, &result))
+		require.Len(t, result.Data.Errors, 1, target)
+		assert.Equal(t, field, result.Data.Errors[0].Field, target)
+	}
+}
@@ -0,0 +1,81 @@
+package integration
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiValueFilters_CommaSeparatedAndRepeatedMatchAny(t *testing.T) {
+	resp, comma := getLinkedPage(t, "/api/v1/listings?property_type=House,Duplex&limit=100")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NotEmpty(t, comma.Data.Items)
+	types := map[string]bool{}
+	for _, listing := range comma.Data.Items {
+		types[listing.GetPropertyType()] = true
+	}
+	assert.Equal(t, map[string]bool{"House": true, "Duplex": true}, types)
+
+	_, repeated := getLinkedPage(t, "/api/v1/listings?property_type=House&property_type=Duplex&limit=100")
+	assert.Equal(t, comma.Data.Meta.Total, repeated.Data.Meta.Total)
+
+	resp, cities := getLinkedPage(t, "/api/v1/listings?city=Lagos&city=Abuja&limit=2")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	_, lagos := getLinkedPage(t, "/api/v1/listings?city=Lagos&limit=1")
+	_, abuja := getLinkedPage(t, "/api/v1/listings?city=Abuja&limit=1")
+	assert.Equal(t, lagos.Data.Meta.Total+abuja.Data.Meta.Total, cities.Data.Meta.Total)
+
+	// Page links keep every value
+	next, err := url.Parse(cities.Data.Meta.Links.Next)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Lagos", "Abuja"}, next.Query()["city"])
+}
+
+func TestMultiValueFilters_Exclusion(t *testing.T) {
+	_, all := getLinkedPage(t, "/api/v1/listings?city=Lagos&limit=100")
+	resp, page := getLinkedPage(t, "/api/v1/listings?city=Lagos&exclude_property_type=House,Penthouse&limit=100")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.NotEmpty(t, page.Data.Items)
+	assert.Less(t, page.Data.Meta.Total, all.Data.Meta.Total)
+	for _, listing := range page.Data.Items {
+		assert.NotContains(t, []string{"House", "Penthouse"}, listing.GetPropertyType())
+		assert.Equal(t, "Lagos", listing.GetCity())
+	}
+}
+
+func TestMultiValueFilters_LocationIsNotSplit(t *testing.T) {
+	_, lagos := getLinkedPage(t, "/api/v1/listings?city=Lagos&limit=100")
+	resp, page := getLinkedPage(t, "/api/v1/listings?location="+url.QueryEscape("Lekki, Lagos")+"&limit=100")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.NotEmpty(t, page.Data.Items)
+	assert.Less(t, page.Data.Meta.Total, lagos.Data.Meta.Total)
+	for _, listing := range page.Data.Items {
+		assert.Contains(t, listing.Location, "Lekki")
+		assert.Equal(t, "Lagos", listing.GetCity())
+	}
+}
+
+func TestMultiValueFilters_ExclusionMatchesExactly(t *testing.T) {
+	_, lagos := getLinkedPage(t, "/api/v1/listings?city=Lagos&limit=100")
+
+	// A partial or misspelt name excludes nothing
+	_, page := getLinkedPage(t, "/api/v1/listings?city=Lagos&exclude_city=Lag&exclude_location=Lekk&limit=100")
+	assert.Equal(t, lagos.Data.Meta.Total, page.Data.Meta.Total)
+
+	// An excluded location drops listings in that area or city, not those merely containing it
+	resp, page := getLinkedPage(t, "/api/v1/listings?exclude_location=lekki&limit=100")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	phaseOne := false
+	for _, listing := range page.Data.Items {
+		assert.NotEqual(t, "Lekki", listing.GetArea())
+		assert.NotEqual(t, "Lekki", listing.GetCity())
+		phaseOne = phaseOne || strings.HasPrefix(listing.Location, "Lekki Phase 1")
+	}
+	assert.True(t, phaseOne)
+}
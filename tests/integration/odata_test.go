@@ -0,0 +1,154 @@
+package integration
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"housing-api/pkg/odata"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// getOData requests an OData URL and decodes the JSON body into out
+func getOData(t *testing.T, target string, out any) *http.Response {
+	t.Helper()
+	resp, err := setupTestApp().Test(httptest.NewRequest("GET", target, nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(body, out), string(body))
+	return resp
+}
+
+func TestOData_QueriesProperties(t *testing.T) {
+	query := url.Values{
+		"$filter":  {"(City eq 'Lagos' or City eq 'Abuja') and ListPrice le 3000000 and BedroomsTotal ge 3"},
+		"$select":  {"ListingKey,ListPrice,BedroomsTotal,City"},
+		"$orderby": {"ListPrice desc"},
+		"$count":   {"true"},
+	}
+	var collection odata.Collection
+	resp := getOData(t, "/api/v1/odata/Property?"+query.Encode(), &collection)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "4.0", resp.Header.Get("OData-Version"))
+	assert.Contains(t, resp.Header.Get("Content-Type"), "odata.metadata=minimal")
+	assert.Contains(t, collection.Context, "/api/v1/odata/$metadata#Property(ListingKey,ListPrice,BedroomsTotal,City)")
+
+	require.NotEmpty(t, collection.Value)
+	require.NotNil(t, collection.Count)
+	assert.Equal(t, int64(len(collection.Value)), *collection.Count)
+	previous := 3000000.0
+	for _, property := range collection.Value {
+		assert.Len(t, property, 4)
+		assert.Contains(t, []any{"Lagos", "Abuja"}, property["City"])
+		assert.GreaterOrEqual(t, property["BedroomsTotal"], 3.0)
+		price := property["ListPrice"].(float64)
+		assert.LessOrEqual(t, price, previous)
+		previous = price
+	}
+}
+
+func TestOData_OrdersByListedPrice(t *testing.T) {
+	// ListPrice sorts by the price as listed, so a nightly price ranks by its nightly amount
+	query := url.Values{
+		"$filter":  {"ListPrice le 1000000"},
+		"$select":  {"ListPrice,LeaseAmountFrequency"},
+		"$orderby": {"ListPrice desc"},
+	}
+	var collection odata.Collection
+	resp := getOData(t, "/api/v1/odata/Property?"+query.Encode(), &collection)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.NotEmpty(t, collection.Value)
+	var frequencies []any
+	previous := 1000000.0
+	for _, property := range collection.Value {
+		price := property["ListPrice"].(float64)
+		assert.LessOrEqual(t, price, previous)
+		previous = price
+		frequencies = append(frequencies, property["LeaseAmountFrequency"])
+	}
+	assert.Contains(t, frequencies, "Daily")
+}
+
+func TestOData_LongFilterChains(t *testing.T) {
+	// A flat chain of and terms is not nesting, however long
+	terms := []string{"City eq 'Abuja'"}
+	for len(terms) < 40 {
+		terms = append(terms, "BedroomsTotal ge 0")
+	}
+	query := url.Values{"$filter": {strings.Join(terms, " and ")}, "$count": {"true"}}
+	var abuja odata.Collection
+	resp := getOData(t, "/api/v1/odata/Property?"+query.Encode(), &abuja)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NotNil(t, abuja.Count)
+	assert.Equal(t, int64(14), *abuja.Count)
+
+	// Filters differing only in a literal don't share cached results
+	query.Set("$filter", strings.Replace(query.Get("$filter"), "Abuja", "Lagos", 1))
+	var lagos odata.Collection
+	getOData(t, "/api/v1/odata/Property?"+query.Encode(), &lagos)
+	require.NotNil(t, lagos.Count)
+	assert.NotEqual(t, *abuja.Count, *lagos.Count)
+}
+
+func TestOData_PagesWithTopAndSkip(t *testing.T) {
+	var first odata.Collection
+	resp := getOData(t, "/api/v1/odata/Property?$top=25&$skip=3&$count=true", &first)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, first.Value, 25)
+	assert.Equal(t, "4", first.Value[0]["ListingKey"])
+	require.NotNil(t, first.Count)
+	assert.Equal(t, int64(40), *first.Count)
+	assert.Empty(t, first.NextLink, "$top is satisfied by the first page")
+
+	var limited odata.Collection
+	getOData(t, "/api/v1/odata/Property?$skip=30", &limited)
+	assert.Len(t, limited.Value, 10)
+	assert.Empty(t, limited.NextLink)
+
+	var property map[string]any
+	resp = getOData(t, "/api/v1/odata/Property('4')?$select=ListingKey,PropertyType,StandardStatus", &property)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "4", property["ListingKey"])
+	assert.Equal(t, "ResidentialLease", property["PropertyType"])
+	assert.Equal(t, "Active", property["StandardStatus"])
+}
+
+func TestOData_RejectsInvalidQueries(t *testing.T) {
+	for target, want := range map[string]odata.ErrorDetail{
+		"/api/v1/odata/Property?$filter=" + url.QueryEscape("City eq 'Lagos' and Colour eq 'red'"): {Target: "$filter", Message: "column 21: unknown or non-filterable property Colour"},
+		"/api/v1/odata/Property?$filter=" + url.QueryEscape("ListPrice gt 'cheap'"):                {Target: "$filter", Message: "column 14: property ListPrice expects a number"},
+		"/api/v1/odata/Property?$orderby=City":                                                     {Target: "$orderby", Message: "non-sortable property City"},
+		"/api/v1/odata/Property?$select=Colour":                                                    {Target: "$select", Message: "unknown property Colour"},
+		"/api/v1/odata/Property?$top=-1":                                                           {Target: "$top", Message: "non-negative integer"},
+		"/api/v1/odata/Property?$expand=Media":                                                     {Target: "$expand", Message: "not supported"},
+		"/api/v1/odata/Property(4)":                                                                {Target: "ListingKey", Message: "quoted string"},
+	} {
+		var result odata.ErrorResponse
+		resp := getOData(t, target, &result)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, target)
+		assert.Equal(t, "BadRequest", result.Error.Code, target)
+		assert.Equal(t, want.Target, result.Error.Target, target)
+		assert.Contains(t, result.Error.Message, want.Message, target)
+	}
+}
+
+func TestOData_ServesMetadata(t *testing.T) {
+	resp, err := setupTestApp().Test(httptest.NewRequest("GET", "/api/v1/odata/$metadata", nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), "application/xml"))
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `<Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="org.reso.metadata">`)
+	assert.Contains(t, string(body), `<Property Name="ListPrice" Type="Edm.Decimal"></Property>`)
+	assert.Contains(t, string(body), `<EntitySet Name="Property" EntityType="org.reso.metadata.Property"></EntitySet>`)
+}
@@ -0,0 +1,113 @@
+package integration
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"housing-api/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type linkedPage struct {
+	Data struct {
+		Items []models.Listing `json:"items"`
+		Meta  models.MetaInfo  `json:"meta"`
+	} `json:"data"`
+}
+
+func getLinkedPage(t *testing.T, target string) (*http.Response, linkedPage) {
+	t.Helper()
+	app := setupTestApp()
+
+	resp, err := app.Test(httptest.NewRequest("GET", target, nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	var page linkedPage
+	require.NoError(t, json.Unmarshal(body, &page))
+	return resp, page
+}
+
+func TestPaginationLinks_PreserveFilters(t *testing.T) {
+	resp, page := getLinkedPage(t, "/api/v1/listings?city=Lagos&min_bedrooms=2&page=2&limit=2")
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	links := page.Data.Meta.Links
+	require.NotNil(t, links)
+
+	for _, link := range []string{links.Self, links.First, links.Prev, links.Last} {
+		u, err := url.Parse(link)
+		require.NoError(t, err)
+		assert.Equal(t, "/api/v1/listings", u.Path)
+		assert.Equal(t, "Lagos", u.Query().Get("city"))
+		assert.Equal(t, "2", u.Query().Get("min_bedrooms"))
+		assert.Equal(t, "2", u.Query().Get("limit"))
+	}
+
+	prev, _ := url.Parse(links.Prev)
+	assert.Equal(t, "1", prev.Query().Get("page"))
+	if page.Data.Meta.TotalPages > 2 {
+		next, _ := url.Parse(links.Next)
+		assert.Equal(t, "3", next.Query().Get("page"))
+	}
+}
+
+func TestPaginationLinks_LinkHeader(t *testing.T) {
+	resp, page := getLinkedPage(t, "/api/v1/listings?page=1&limit=2")
+
+	header := resp.Header.Get("Link")
+	links := page.Data.Meta.Links
+	require.NotNil(t, links)
+	assert.Contains(t, header, "<"+links.First+`>; rel="first"`)
+	assert.Contains(t, header, "<"+links.Next+`>; rel="next"`)
+	assert.Contains(t, header, "<"+links.Last+`>; rel="last"`)
+	assert.NotContains(t, header, `rel="prev"`)
+	assert.Empty(t, links.Prev)
+}
+
+func TestPaginationLinks_PageBeyondEnd(t *testing.T) {
+	resp, page := getLinkedPage(t, "/api/v1/listings?page=9999&limit=10")
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, page.Data.Items)
+	meta := page.Data.Meta
+	assert.Greater(t, meta.Total, int64(0))
+	require.NotNil(t, meta.Links)
+	assert.Empty(t, meta.Links.Next)
+	assert.Equal(t, meta.Links.Last, meta.Links.Prev)
+}
+
+func TestPaginationLinks_Search(t *testing.T) {
+	_, page := getLinkedPage(t, "/api/v1/listings/search?q=Lagos&limit=1")
+
+	require.NotNil(t, page.Data.Meta.Links)
+	u, err := url.Parse(page.Data.Meta.Links.Self)
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/listings/search", u.Path)
+	assert.Equal(t, "Lagos", u.Query().Get("q"))
+}
+
+func TestPaginationLinks_IgnoreHostHeader(t *testing.T) {
+	// Links end up in cached bodies, so a client-supplied Host must not leak into them
+	req := httptest.NewRequest("GET", "/api/v1/listings?page=1&limit=2", nil)
+	req.Host = "attacker.example"
+	resp, err := setupTestApp().Test(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	var page linkedPage
+	require.NoError(t, json.Unmarshal(body, &page))
+	require.NotNil(t, page.Data.Meta.Links)
+	assert.True(t, strings.HasPrefix(page.Data.Meta.Links.Next, "/api/v1/listings?"), page.Data.Meta.Links.Next)
+	assert.NotContains(t, string(body), "attacker.example")
+	assert.NotContains(t, resp.Header.Get("Link"), "attacker.example")
+}
@@ -0,0 +1,65 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"housing-api/api/routes"
+	"housing-api/internal/config"
+	"housing-api/internal/middleware/requestid"
+	"housing-api/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRequestIDTestApp() *fiber.App {
+	app := fiber.New()
+	app.Use(requestid.RequestID())
+	cfg, _ := config.Load()
+	routes.Setup(app, cfg)
+	return app
+}
+
+func TestRequestID_GeneratedWhenMissing(t *testing.T) {
+	app := setupRequestIDTestApp()
+
+	req := httptest.NewRequest("GET", "/api/v1/listings", nil)
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotEmpty(t, resp.Header.Get(requestid.HeaderName))
+}
+
+func TestRequestID_PropagatedToErrorResponse(t *testing.T) {
+	app := setupRequestIDTestApp()
+
+	req := httptest.NewRequest("GET", "/api/v1/listings/9999", nil)
+	req.Header.Set(requestid.HeaderName, "support-ticket-42")
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "support-ticket-42", resp.Header.Get(requestid.HeaderName))
+
+	var response models.APIResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+	require.NotNil(t, response.Error)
+	assert.Equal(t, "support-ticket-42", response.Error.RequestID)
+}
+
+func TestRequestID_InvalidHeaderReplaced(t *testing.T) {
+	app := setupRequestIDTestApp()
+
+	req := httptest.NewRequest("GET", "/api/v1/listings", nil)
+	req.Header.Set(requestid.HeaderName, "bad id with spaces")
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.NotEqual(t, "bad id with spaces", resp.Header.Get(requestid.HeaderName))
+	assert.NotEmpty(t, resp.Header.Get(requestid.HeaderName))
+}
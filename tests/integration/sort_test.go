@@ -0,0 +1,32 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSort_UnknownFieldIsValidationError(t *testing.T) {
+	app := setupTestApp()
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/listings?sort=-colour", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestSort_NewestFirst(t *testing.T) {
+	resp, page := getLinkedPage(t, "/api/v1/listings?sort=newest&limit=5")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, page.Data.Items, 5)
+
+	for i := 1; i < len(page.Data.Items); i++ {
+		assert.Greater(t, page.Data.Items[i-1].ID, page.Data.Items[i].ID)
+	}
+	next, err := url.Parse(page.Data.Meta.Links.Next)
+	require.NoError(t, err)
+	assert.Equal(t, "newest", next.Query().Get("sort"))
+}
@@ -0,0 +1,59 @@
+package integration
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"housing-api/pkg/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getSuggestions(t *testing.T, target string) (int, []search.Completion) {
+	t.Helper()
+	app := setupTestApp()
+
+	resp, err := app.Test(httptest.NewRequest("GET", target, nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	var result struct {
+		Data []search.Completion `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &result))
+	return resp.StatusCode, result.Data
+}
+
+func TestSuggest_CompletesAreasAndCitiesWithCounts(t *testing.T) {
+	status, suggestions := getSuggestions(t, "/api/v1/listings/suggest?q=lek")
+	require.Equal(t, http.StatusOK, status)
+	require.NotEmpty(t, suggestions)
+
+	assert.Equal(t, "Lekki", suggestions[0].Text)
+	assert.Equal(t, "area", suggestions[0].Kind)
+	for _, suggestion := range suggestions {
+		assert.Positive(t, suggestion.Count)
+	}
+}
+
+func TestSuggest_MatchesLaterWordsAndTitles(t *testing.T) {
+	_, suggestions := getSuggestions(t, "/api/v1/listings/suggest?q=pent&limit=5")
+	require.NotEmpty(t, suggestions)
+	assert.Equal(t, search.Completion{Text: "Penthouse", Kind: "property_type", Count: 2}, suggestions[0])
+
+	var kinds []string
+	for _, suggestion := range suggestions {
+		kinds = append(kinds, suggestion.Kind)
+	}
+	assert.Contains(t, kinds, "title")
+}
+
+func TestSuggest_RequiresQuery(t *testing.T) {
+	status, _ := getSuggestions(t, "/api/v1/listings/suggest?q=")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
@@ -0,0 +1,95 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"housing-api/api/routes"
+	"housing-api/internal/config"
+	tracingmw "housing-api/internal/middleware/tracing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordSpans installs a tracer provider recording every span until the test ends
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return recorder
+}
+
+// findSpan returns the ended span with the given name
+func findSpan(t *testing.T, spans []sdktrace.ReadOnlySpan, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	for _, span := range spans {
+		if span.Name() == name {
+			return span
+		}
+	}
+	require.Failf(t, "span not recorded", "no span named %q", name)
+	return nil
+}
+
+func TestTracing_ContinuesIncomingTraceAcrossLayers(t *testing.T) {
+	recorder := recordSpans(t)
+	app := fiber.New()
+	app.Use(tracingmw.Tracing())
+	cfg, _ := config.Load()
+	routes.Setup(app, cfg)
+
+	const traceID, parentID = "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"
+	req := httptest.NewRequest("GET", "/api/v1/listings?city=Lagos&limit=5", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-"+parentID+"-01")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	spans := recorder.Ended()
+	server := findSpan(t, spans, "GET /api/v1/listings/")
+	service := findSpan(t, spans, "ListingService.GetListings")
+	repository := findSpan(t, spans, "ListingRepository.GetOffset")
+
+	// The request span joins the caller's trace as a child of its span
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind())
+	assert.Equal(t, traceID, server.SpanContext().TraceID().String())
+	assert.Equal(t, parentID, server.Parent().SpanID().String())
+	assert.True(t, server.Parent().IsRemote())
+
+	// Service and repository spans nest beneath it
+	assert.Equal(t, server.SpanContext().SpanID(), service.Parent().SpanID())
+	assert.Equal(t, service.SpanContext().SpanID(), repository.Parent().SpanID())
+	assert.Equal(t, traceID, repository.SpanContext().TraceID().String())
+}
+
+func TestTracing_StartsNewTraceWithoutTraceparent(t *testing.T) {
+	recorder := recordSpans(t)
+	app := fiber.New()
+	app.Use(tracingmw.Tracing())
+	app.Get("/traced/:id", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/traced/7", nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// The span is named by route pattern, not the raw path
+	server := findSpan(t, recorder.Ended(), "GET /traced/:id")
+	assert.False(t, server.Parent().IsValid())
+	assert.True(t, server.SpanContext().IsValid())
+}
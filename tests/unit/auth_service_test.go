@@ -1,13 +1,18 @@
 package unit
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"housing-api/internal/config"
 	"housing-api/internal/models"
+	"housing-api/internal/repositories"
 	"housing-api/internal/services"
+	"housing-api/pkg/shutdown"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -34,27 +39,60 @@ func cleanupAuthTestEnvironment() {
 	os.RemoveAll("../../testdata")
 }
 
+// newAuthService builds a service over a user store on cfg.UsersFile
+func newAuthService(t *testing.T, cfg *config.Config) *services.AuthService {
+	t.Helper()
+	users, err := repositories.NewUserRepository(cfg.UsersFile)
+	require.NoError(t, err)
+	return services.NewAuthService(cfg, users)
+}
+
 func TestAuthService_NewAuthService(t *testing.T) {
 	cfg := setupAuthTestEnvironment()
 	defer cleanupAuthTestEnvironment()
 
-	service := services.NewAuthService(cfg)
+	service := newAuthService(t, cfg)
 
 	assert.NotNil(t, service)
 }
 
+func TestAuthService_SavesUsersToFile(t *testing.T) {
+	cfg := setupAuthTestEnvironment()
+	defer cleanupAuthTestEnvironment()
+	cfg.UsersFile = filepath.Join(t.TempDir(), "users.json")
+
+	service := newAuthService(t, cfg)
+	_, err := service.Register(context.Background(), models.RegisterRequest{Email: "saved@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	// Shutdown waits for the background write
+	require.NoError(t, shutdown.Run(context.Background()))
+	data, err := os.ReadFile(cfg.UsersFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "saved@example.com")
+	assert.Contains(t, string(data), "password_hash")
+
+	// A new store picks the registered user up, keeping a single demo user
+	restarted := newAuthService(t, cfg)
+	_, err = restarted.Login(context.Background(), models.LoginRequest{Email: "saved@example.com", Password: "password123"})
+	assert.NoError(t, err)
+	_, err = restarted.Login(context.Background(), models.LoginRequest{Email: "test-unit@worksquare.com", Password: "testunit123"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "test-unit@worksquare.com"))
+}
+
 func TestAuthService_Login_Success(t *testing.T) {
 	cfg := setupAuthTestEnvironment()
 	defer cleanupAuthTestEnvironment()
 
-	service := services.NewAuthService(cfg)
+	service := newAuthService(t, cfg)
 
 	loginReq := models.LoginRequest{
 		Email:    "test-unit@worksquare.com",
 		Password: "testunit123",
 	}
 
-	authResponse, err := service.Login(loginReq)
+	authResponse, err := service.Login(context.Background(), loginReq)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, authResponse)
@@ -62,7 +100,7 @@ func TestAuthService_Login_Success(t *testing.T) {
 	assert.NotEmpty(t, authResponse.RefreshToken)
 	assert.Equal(t, "test-unit@worksquare.com", authResponse.User.Email)
 	assert.Greater(t, authResponse.ExpiresIn, int64(0))
-	
+
 	// Verify user data doesn't contain password
 	assert.NotEmpty(t, authResponse.User.Email)
 	assert.Greater(t, authResponse.User.ID, 0)
@@ -72,7 +110,7 @@ func TestAuthService_Login_InvalidCredentials(t *testing.T) {
 	cfg := setupAuthTestEnvironment()
 	defer cleanupAuthTestEnvironment()
 
-	service := services.NewAuthService(cfg)
+	service := newAuthService(t, cfg)
 
 	testCases := []struct {
 		name     string
@@ -103,7 +141,7 @@ func TestAuthService_Login_InvalidCredentials(t *testing.T) {
 				Password: tc.password,
 			}
 
-			authResponse, err := service.Login(loginReq)
+			authResponse, err := service.Login(context.Background(), loginReq)
 
 			assert.Error(t, err)
 			assert.Nil(t, authResponse)
@@ -116,14 +154,14 @@ func TestAuthService_Register_Success(t *testing.T) {
 	cfg := setupAuthTestEnvironment()
 	defer cleanupAuthTestEnvironment()
 
-	service := services.NewAuthService(cfg)
+	service := newAuthService(t, cfg)
 
 	registerReq := models.RegisterRequest{
 		Email:    "newuser@test.com",
 		Password: "newpassword123",
 	}
 
-	authResponse, err := service.Register(registerReq)
+	authResponse, err := service.Register(context.Background(), registerReq)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, authResponse)
@@ -139,7 +177,7 @@ func TestAuthService_Register_Success(t *testing.T) {
 		Password: "newpassword123",
 	}
 
-	loginResponse, err := service.Login(loginReq)
+	loginResponse, err := service.Login(context.Background(), loginReq)
 	assert.NoError(t, err)
 	assert.NotNil(t, loginResponse)
 	assert.Equal(t, authResponse.User.Email, loginResponse.User.Email)
@@ -149,7 +187,7 @@ func TestAuthService_Register_DuplicateEmail(t *testing.T) {
 	cfg := setupAuthTestEnvironment()
 	defer cleanupAuthTestEnvironment()
 
-	service := services.NewAuthService(cfg)
+	service := newAuthService(t, cfg)
 
 	// First registration
 	registerReq := models.RegisterRequest{
@@ -157,12 +195,12 @@ func TestAuthService_Register_DuplicateEmail(t *testing.T) {
 		Password: "password123",
 	}
 
-	authResponse1, err := service.Register(registerReq)
+	authResponse1, err := service.Register(context.Background(), registerReq)
 	assert.NoError(t, err)
 	assert.NotNil(t, authResponse1)
 
 	// Second registration with same email
-	authResponse2, err := service.Register(registerReq)
+	authResponse2, err := service.Register(context.Background(), registerReq)
 	assert.Error(t, err)
 	assert.Nil(t, authResponse2)
 	assert.Contains(t, err.Error(), "already exists")
@@ -172,7 +210,7 @@ func TestAuthService_RefreshToken_Success(t *testing.T) {
 	cfg := setupAuthTestEnvironment()
 	defer cleanupAuthTestEnvironment()
 
-	service := services.NewAuthService(cfg)
+	service := newAuthService(t, cfg)
 
 	// First, login to get refresh token
 	loginReq := models.LoginRequest{
@@ -180,7 +218,7 @@ func TestAuthService_RefreshToken_Success(t *testing.T) {
 		Password: "testunit123",
 	}
 
-	loginResponse, err := service.Login(loginReq)
+	loginResponse, err := service.Login(context.Background(), loginReq)
 	require.NoError(t, err)
 	require.NotNil(t, loginResponse)
 
@@ -203,7 +241,7 @@ func TestAuthService_RefreshToken_InvalidToken(t *testing.T) {
 	cfg := setupAuthTestEnvironment()
 	defer cleanupAuthTestEnvironment()
 
-	service := services.NewAuthService(cfg)
+	service := newAuthService(t, cfg)
 
 	testCases := []struct {
 		name  string
@@ -242,7 +280,7 @@ func TestAuthService_ValidateToken_Success(t *testing.T) {
 	cfg := setupAuthTestEnvironment()
 	defer cleanupAuthTestEnvironment()
 
-	service := services.NewAuthService(cfg)
+	service := newAuthService(t, cfg)
 
 	// Login to get access token
 	loginReq := models.LoginRequest{
@@ -250,7 +288,7 @@ func TestAuthService_ValidateToken_Success(t *testing.T) {
 		Password: "testunit123",
 	}
 
-	authResponse, err := service.Login(loginReq)
+	authResponse, err := service.Login(context.Background(), loginReq)
 	require.NoError(t, err)
 	require.NotNil(t, authResponse)
 
@@ -267,7 +305,7 @@ func TestAuthService_ValidateToken_InvalidToken(t *testing.T) {
 	cfg := setupAuthTestEnvironment()
 	defer cleanupAuthTestEnvironment()
 
-	service := services.NewAuthService(cfg)
+	service := newAuthService(t, cfg)
 
 	testCases := []struct {
 		name  string
@@ -301,7 +339,7 @@ func TestAuthService_GetUserByID_Success(t *testing.T) {
 	cfg := setupAuthTestEnvironment()
 	defer cleanupAuthTestEnvironment()
 
-	service := services.NewAuthService(cfg)
+	service := newAuthService(t, cfg)
 
 	// Get demo user (ID should be 1)
 	user, err := service.GetUserByID(1)
@@ -317,7 +355,7 @@ func TestAuthService_GetUserByID_NotFound(t *testing.T) {
 	cfg := setupAuthTestEnvironment()
 	defer cleanupAuthTestEnvironment()
 
-	service := services.NewAuthService(cfg)
+	service := newAuthService(t, cfg)
 
 	user, err := service.GetUserByID(9999)
 
@@ -330,7 +368,7 @@ func TestAuthService_MultipleUsers(t *testing.T) {
 	cfg := setupAuthTestEnvironment()
 	defer cleanupAuthTestEnvironment()
 
-	service := services.NewAuthService(cfg)
+	service := newAuthService(t, cfg)
 
 	// Register multiple users
 	users := []models.RegisterRequest{
@@ -342,10 +380,10 @@ func TestAuthService_MultipleUsers(t *testing.T) {
 	var userIDs []int
 
 	for _, userReq := range users {
-		authResponse, err := service.Register(userReq)
+		authResponse, err := service.Register(context.Background(), userReq)
 		require.NoError(t, err)
 		require.NotNil(t, authResponse)
-		
+
 		userIDs = append(userIDs, authResponse.User.ID)
 
 		// Verify each user can login
@@ -355,7 +393,7 @@ func TestAuthService_MultipleUsers(t *testing.T) {
 			Password: userReq.Password,
 		}
 
-		loginResponse, err := service.Login(loginReq)
+		loginResponse, err := service.Login(context.Background(), loginReq)
 		assert.NoError(t, err)
 		assert.NotNil(t, loginResponse)
 		assert.Equal(t, userReq.Email, loginResponse.User.Email)
@@ -376,7 +414,7 @@ func TestAuthService_ConcurrentOperations(t *testing.T) {
 	cfg := setupAuthTestEnvironment()
 	defer cleanupAuthTestEnvironment()
 
-	service := services.NewAuthService(cfg)
+	service := newAuthService(t, cfg)
 
 	// Test concurrent login attempts
 	concurrency := 10
@@ -389,7 +427,7 @@ func TestAuthService_ConcurrentOperations(t *testing.T) {
 				Password: "testunit123",
 			}
 
-			_, err := service.Login(loginReq)
+			_, err := service.Login(context.Background(), loginReq)
 			results <- err
 		}(i)
 	}
@@ -408,7 +446,7 @@ func TestAuthService_ConcurrentRegistrations(t *testing.T) {
 	cfg := setupAuthTestEnvironment()
 	defer cleanupAuthTestEnvironment()
 
-	service := services.NewAuthService(cfg)
+	service := newAuthService(t, cfg)
 
 	// Test concurrent registration attempts with different emails
 	concurrency := 5
@@ -421,7 +459,7 @@ func TestAuthService_ConcurrentRegistrations(t *testing.T) {
 				Password: "password123",
 			}
 
-			_, err := service.Register(registerReq)
+			_, err := service.Register(context.Background(), registerReq)
 			results <- err
 		}(i)
 	}
@@ -443,7 +481,7 @@ func TestAuthService_PasswordSecurity(t *testing.T) {
 	cfg := setupAuthTestEnvironment()
 	defer cleanupAuthTestEnvironment()
 
-	service := services.NewAuthService(cfg)
+	service := newAuthService(t, cfg)
 
 	// Register a user
 	registerReq := models.RegisterRequest{
@@ -451,7 +489,7 @@ func TestAuthService_PasswordSecurity(t *testing.T) {
 		Password: "plainpassword123",
 	}
 
-	authResponse, err := service.Register(registerReq)
+	authResponse, err := service.Register(context.Background(), registerReq)
 	require.NoError(t, err)
 	require.NotNil(t, authResponse)
 
@@ -474,7 +512,7 @@ func TestAuthService_TokenIntegrity(t *testing.T) {
 	cfg := setupAuthTestEnvironment()
 	defer cleanupAuthTestEnvironment()
 
-	service := services.NewAuthService(cfg)
+	service := newAuthService(t, cfg)
 
 	// Login to get tokens
 	loginReq := models.LoginRequest{
@@ -482,7 +520,7 @@ func TestAuthService_TokenIntegrity(t *testing.T) {
 		Password: "testunit123",
 	}
 
-	authResponse, err := service.Login(loginReq)
+	authResponse, err := service.Login(context.Background(), loginReq)
 	require.NoError(t, err)
 	require.NotNil(t, authResponse)
 
@@ -507,7 +545,7 @@ func TestAuthService_EdgeCases(t *testing.T) {
 	cfg := setupAuthTestEnvironment()
 	defer cleanupAuthTestEnvironment()
 
-	service := services.NewAuthService(cfg)
+	service := newAuthService(t, cfg)
 
 	t.Run("Empty email registration", func(t *testing.T) {
 		registerReq := models.RegisterRequest{
@@ -515,7 +553,7 @@ func TestAuthService_EdgeCases(t *testing.T) {
 			Password: "password123",
 		}
 
-		authResponse, err := service.Register(registerReq)
+		authResponse, err := service.Register(context.Background(), registerReq)
 		// This should be handled by validation layer, but service should be robust
 		assert.Error(t, err)
 		assert.Nil(t, authResponse)
@@ -528,7 +566,7 @@ func TestAuthService_EdgeCases(t *testing.T) {
 			Password: "password123",
 		}
 
-		authResponse1, err := service.Register(registerReq)
+		authResponse1, err := service.Register(context.Background(), registerReq)
 		require.NoError(t, err)
 		require.NotNil(t, authResponse1)
 
@@ -538,7 +576,7 @@ func TestAuthService_EdgeCases(t *testing.T) {
 			Password: "password123",
 		}
 
-		authResponse2, err := service.Login(loginReq)
+		authResponse2, err := service.Login(context.Background(), loginReq)
 		assert.NoError(t, err) // Should work (case insensitive)
 		assert.NotNil(t, authResponse2)
 		assert.Equal(t, authResponse1.User.ID, authResponse2.User.ID)
@@ -549,7 +587,7 @@ func TestAuthService_MemoryManagement(t *testing.T) {
 	cfg := setupAuthTestEnvironment()
 	defer cleanupAuthTestEnvironment()
 
-	service := services.NewAuthService(cfg)
+	service := newAuthService(t, cfg)
 
 	// Create many users to test memory usage
 	userCount := 100
@@ -559,7 +597,7 @@ func TestAuthService_MemoryManagement(t *testing.T) {
 			Password: "password123",
 		}
 
-		authResponse, err := service.Register(registerReq)
+		authResponse, err := service.Register(context.Background(), registerReq)
 		assert.NoError(t, err)
 		assert.NotNil(t, authResponse)
 
@@ -569,7 +607,7 @@ func TestAuthService_MemoryManagement(t *testing.T) {
 			Password: registerReq.Password,
 		}
 
-		loginResponse, err := service.Login(loginReq)
+		loginResponse, err := service.Login(context.Background(), loginReq)
 		assert.NoError(t, err)
 		assert.NotNil(t, loginResponse)
 	}
@@ -580,4 +618,4 @@ func TestAuthService_MemoryManagement(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotNil(t, user)
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,102 @@
+package unit
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"housing-api/pkg/cache"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := cache.New[int]("unit_lru", 2, 0)
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	_, _ = c.Get("a") // a is now more recent than b
+	c.Set("c", 3)
+
+	_, ok := c.Get("b")
+	assert.False(t, ok)
+	value, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+}
+
+func TestCache_ExpiresEntries(t *testing.T) {
+	c := cache.New[int]("unit_ttl", 10, 20*time.Millisecond)
+	c.Set("a", 1)
+
+	time.Sleep(40 * time.Millisecond)
+
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Stats().Entries)
+}
+
+func TestCache_CollapsesConcurrentLoads(t *testing.T) {
+	c := cache.New[int]("unit_singleflight", 10, time.Minute)
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := c.GetOrLoad("key", func() (int, error) {
+				loads.Add(1)
+				<-release
+				return 42, nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, 42, value)
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), loads.Load())
+}
+
+func TestCache_DoesNotCacheErrors(t *testing.T) {
+	c := cache.New[int]("unit_errors", 10, time.Minute)
+
+	_, err := c.GetOrLoad("key", func() (int, error) { return 0, errors.New("boom") })
+	require.Error(t, err)
+
+	value, err := c.GetOrLoad("key", func() (int, error) { return 7, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 7, value)
+}
+
+func TestCache_PurgeDiscardsInFlightLoad(t *testing.T) {
+	c := cache.New[int]("unit_purge", 10, time.Minute)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = c.GetOrLoad("key", func() (int, error) {
+			close(started)
+			<-release
+			return 1, nil
+		})
+	}()
+
+	<-started
+	c.Purge()
+	close(release)
+	<-done
+
+	_, ok := c.Get("key")
+	assert.False(t, ok, "a load that began before Purge must not repopulate the cache")
+}
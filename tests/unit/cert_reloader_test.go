@@ -0,0 +1,90 @@
+package unit
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"housing-api/pkg/tlsutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSelfSignedCert writes a self-signed certificate/key pair for commonName into dir
+func writeSelfSignedCert(t *testing.T, dir, commonName string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "server.crt")
+	keyFile := filepath.Join(dir, "server.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+
+	return certFile, keyFile
+}
+
+func servedCommonName(t *testing.T, reloader *tlsutil.CertReloader) string {
+	cert, err := reloader.GetCertificate(nil)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	return leaf.Subject.CommonName
+}
+
+func TestCertReloader_PicksUpRenewedCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeSelfSignedCert(t, dir, "original")
+
+	reloader, err := tlsutil.NewCertReloader(certFile, keyFile, time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, "original", servedCommonName(t, reloader))
+
+	// Renew the certificate in place with a newer modification time
+	writeSelfSignedCert(t, dir, "renewed")
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, future, future))
+	time.Sleep(5 * time.Millisecond)
+
+	assert.Equal(t, "renewed", servedCommonName(t, reloader))
+}
+
+func TestCertReloader_KeepsCertificateWhenRenewalIsBroken(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeSelfSignedCert(t, dir, "original")
+
+	reloader, err := tlsutil.NewCertReloader(certFile, keyFile, time.Millisecond)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(certFile, []byte("not a certificate"), 0600))
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, future, future))
+	time.Sleep(5 * time.Millisecond)
+
+	assert.Equal(t, "original", servedCommonName(t, reloader))
+}
+
+func TestCertReloader_MissingFiles(t *testing.T) {
+	_, err := tlsutil.NewCertReloader("missing.crt", "missing.key", time.Minute)
+	assert.Error(t, err)
+}
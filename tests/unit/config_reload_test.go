@@ -0,0 +1,151 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"housing-api/internal/config"
+	"housing-api/internal/middleware/hotswap"
+	"housing-api/internal/middleware/ratelimit"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigReload_AppliesLiveSettingsAndReportsRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("port: 4000\nlog_level: info\nrate_limit_max_requests: 100\n"), 0o644))
+
+	t.Setenv("PORT", "")
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("RATE_LIMIT_MAX_REQUESTS", "")
+
+	cfg, err := config.LoadWithArgs([]string{"--config=" + path})
+	require.NoError(t, err)
+	config.SetActive(cfg)
+	defer config.SetActive(nil)
+
+	var notified *config.Config
+	t.Cleanup(config.OnReload("unit_rate_limit", []string{"RATE_LIMIT_MAX_REQUESTS"}, func(next *config.Config) {
+		notified = next
+	}))
+
+	require.NoError(t, os.WriteFile(path, []byte("port: 5000\nlog_level: debug\nrate_limit_max_requests: 5\n"), 0o644))
+
+	result, err := config.Reload()
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"LOG_LEVEL", "RATE_LIMIT_MAX_REQUESTS"}, result.Applied)
+	assert.Equal(t, []string{"PORT"}, result.RequiresRestart)
+
+	require.NotNil(t, notified)
+	assert.Equal(t, 5, notified.RateLimitMaxRequests)
+	assert.Equal(t, "4000", config.Active().Port, "restart-only settings keep their running value")
+	assert.Equal(t, "debug", config.Active().LogLevel)
+	assert.Equal(t, "info", cfg.LogLevel, "the previous config is not mutated")
+}
+
+func TestConfigReload_RejectsInvalidConfiguration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("log_level: info\n"), 0o644))
+	t.Setenv("LOG_LEVEL", "")
+
+	cfg, err := config.LoadWithArgs([]string{"--config=" + path})
+	require.NoError(t, err)
+	config.SetActive(cfg)
+	defer config.SetActive(nil)
+
+	require.NoError(t, os.WriteFile(path, []byte("log_level: loud\n"), 0o644))
+
+	_, err = config.Reload()
+
+	assert.Equal(t, []string{"LOG_LEVEL"}, problemKeys(t, err))
+	assert.Same(t, cfg, config.Active())
+}
+
+func TestConfigReload_RemovedHookIsNotNotified(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("log_level: info\n"), 0o644))
+	t.Setenv("LOG_LEVEL", "")
+
+	cfg, err := config.LoadWithArgs([]string{"--config=" + path})
+	require.NoError(t, err)
+	config.SetActive(cfg)
+	defer config.SetActive(nil)
+
+	notified := false
+	remove := config.OnReload("unit_removed", []string{"LOG_LEVEL"}, func(*config.Config) { notified = true })
+	remove()
+
+	require.NoError(t, os.WriteFile(path, []byte("log_level: debug\n"), 0o644))
+	_, err = config.Reload()
+	require.NoError(t, err)
+	assert.False(t, notified)
+}
+
+func TestConfigReload_PicksUpDotenvEdits(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	// RATE_LIMIT_MAX_REQUESTS comes from .env; LOG_LEVEL is set in the real environment and wins
+	t.Setenv("RATE_LIMIT_MAX_REQUESTS", "")
+	require.NoError(t, os.Unsetenv("RATE_LIMIT_MAX_REQUESTS"))
+	t.Setenv("LOG_LEVEL", "warn")
+	require.NoError(t, os.WriteFile(".env", []byte("RATE_LIMIT_MAX_REQUESTS=7\nLOG_LEVEL=debug\n"), 0o644))
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	config.SetActive(cfg)
+	defer config.SetActive(nil)
+	require.Equal(t, 7, cfg.RateLimitMaxRequests)
+
+	require.NoError(t, os.WriteFile(".env", []byte("RATE_LIMIT_MAX_REQUESTS=9\nLOG_LEVEL=error\n"), 0o644))
+	result, err := config.Reload()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"RATE_LIMIT_MAX_REQUESTS"}, result.Applied)
+	assert.Equal(t, 9, config.Active().RateLimitMaxRequests)
+	assert.Equal(t, "warn", config.Active().LogLevel)
+
+	// A variable removed from .env is no longer set
+	require.NoError(t, os.WriteFile(".env", []byte("LOG_LEVEL=error\n"), 0o644))
+	_, err = config.Reload()
+	require.NoError(t, err)
+	_, set := os.LookupEnv("RATE_LIMIT_MAX_REQUESTS")
+	assert.False(t, set)
+}
+
+func TestConfigReload_RateLimitKeepsCounters(t *testing.T) {
+	cfg, _ := config.Load()
+	cfg.RateLimitMaxRequests = 2
+	cfg.RateLimitWindowMS = time.Minute
+
+	storage := ratelimit.NewStorage()
+	limiter := hotswap.New(ratelimit.RateLimiter(cfg, storage))
+	app := fiber.New()
+	app.Use(limiter.Handle)
+	app.Get("/limited", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	request := func() int {
+		resp, err := app.Test(httptest.NewRequest("GET", "/limited", nil))
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+	assert.Equal(t, http.StatusOK, request())
+	assert.Equal(t, http.StatusOK, request())
+
+	// A raised limit allows one more request in the current window, not a fresh allowance
+	next := *cfg
+	next.RateLimitMaxRequests = 3
+	limiter.Swap(ratelimit.RateLimiter(&next, storage))
+	assert.Equal(t, http.StatusOK, request())
+	assert.Equal(t, http.StatusTooManyRequests, request())
+}
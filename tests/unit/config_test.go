@@ -0,0 +1,138 @@
+package unit
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"housing-api/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func problemKeys(t *testing.T, err error) []string {
+	var cfgErr *config.Error
+	require.True(t, errors.As(err, &cfgErr), "expected *config.Error, got %v", err)
+
+	keys := make([]string, 0, len(cfgErr.Problems))
+	for _, problem := range cfgErr.Problems {
+		keys = append(keys, problem.Key)
+	}
+	return keys
+}
+
+func TestConfig_ReportsEveryInvalidKey(t *testing.T) {
+	t.Setenv("RATE_LIMIT_WINDOW_MS", "15 minutes")
+	t.Setenv("RATE_LIMIT_MAX_REQUESTS", "lots")
+	t.Setenv("LOG_LEVEL", "verbose")
+
+	cfg, err := config.Load()
+
+	require.Error(t, err)
+	require.NotNil(t, cfg)
+	assert.ElementsMatch(t, []string{"RATE_LIMIT_WINDOW_MS", "RATE_LIMIT_MAX_REQUESTS", "LOG_LEVEL"}, problemKeys(t, err))
+}
+
+func TestConfig_LayersFileEnvAndFlags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("port: 4000\nlog_level: warn\nrate_limit_max_requests: 50\njwt_refresh_expires_in: 7d\n"), 0o644))
+
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("LOG_LEVEL", "error")
+	t.Setenv("RATE_LIMIT_MAX_REQUESTS", "")
+	t.Setenv("JWT_REFRESH_EXPIRES_IN", "")
+
+	cfg, err := config.LoadWithArgs([]string{"--port=5000"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "5000", cfg.Port)
+	assert.Equal(t, "error", cfg.LogLevel)
+	assert.Equal(t, 50, cfg.RateLimitMaxRequests)
+	assert.Equal(t, 7*24*time.Hour, cfg.JWTRefreshExpiresIn)
+}
+
+func TestConfig_LoadsTOMLFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte("rate_limit_window_ms = 60000\ntracing_sample_ratio = 0.25\n"), 0o644))
+
+	cfg, err := config.LoadWithArgs([]string{"--config", path})
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Minute, cfg.RateLimitWindowMS)
+	assert.Equal(t, 0.25, cfg.TracingSampleRatio)
+}
+
+func TestConfig_RejectsUnknownFileKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("prot: 4000\n"), 0o644))
+
+	_, err := config.LoadWithArgs([]string{"--config=" + path})
+
+	assert.Contains(t, problemKeys(t, err), "prot")
+}
+
+func TestConfig_ProductionRefusesDefaults(t *testing.T) {
+	t.Setenv("NODE_ENV", "production")
+	t.Setenv("JWT_SECRET", config.DefaultJWTSecret)
+	t.Setenv("DEMO_USER_EMAIL", config.DefaultDemoUserEmail)
+	t.Setenv("DEMO_USER_PASSWORD", config.DefaultDemoUserPassword)
+
+	_, err := config.Load()
+
+	assert.ElementsMatch(t, []string{"JWT_SECRET", "DEMO_USER_EMAIL", "DEMO_USER_PASSWORD"}, problemKeys(t, err))
+}
+
+func TestConfig_PrintRedactsSecrets(t *testing.T) {
+	t.Setenv("JWT_SECRET", "a-very-private-signing-secret")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, cfg.Print(&out))
+
+	assert.NotContains(t, out.String(), "a-very-private-signing-secret")
+	assert.Contains(t, out.String(), "[REDACTED]")
+}
+
+func TestConfig_RejectsCredentialedWildcardOrigin(t *testing.T) {
+	t.Setenv("CORS_ALLOW_ORIGINS", "*")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+
+	_, err := config.Load()
+
+	assert.Equal(t, []string{"CORS_ALLOW_ORIGINS"}, problemKeys(t, err))
+}
+
+func TestConfig_AppliesEnvironmentSection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+cors_allow_origins: "*"
+environments:
+  staging:
+    cors_allow_origins: [https://staging.worksquare.com]
+    cors_allow_credentials: true
+`), 0o644))
+	t.Setenv("NODE_ENV", "staging")
+
+	cfg, err := config.LoadWithArgs([]string{"--config=" + path})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"https://staging.worksquare.com"}, cfg.CORSAllowOrigins)
+	assert.True(t, cfg.CORSAllowCredentials)
+}
+
+func TestConfig_FacetPriceBucketsMustIncrease(t *testing.T) {
+	t.Setenv("FACET_PRICE_BUCKETS", "500000, 2000000")
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, []int{500000, 2000000}, cfg.FacetPriceBuckets)
+
+	t.Setenv("FACET_PRICE_BUCKETS", "2000000,500000")
+	_, err = config.Load()
+	assert.Equal(t, []string{"FACET_PRICE_BUCKETS"}, problemKeys(t, err))
+}
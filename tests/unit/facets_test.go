@@ -0,0 +1,102 @@
+package unit
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"housing-api/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// facetCounts flattens a facet into value -> count
+func facetCounts(facet []models.FacetCount) map[string]int {
+	counts := make(map[string]int, len(facet))
+	for _, count := range facet {
+		counts[count.Value] = count.Count
+	}
+	return counts
+}
+
+func TestParseFacets(t *testing.T) {
+	names, err := models.ParseFacets(" City,price,city ")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"city", "price"}, names)
+
+	names, err = models.ParseFacets("all")
+	require.NoError(t, err)
+	assert.Equal(t, models.FacetNames, names)
+
+	names, err = models.ParseFacets("")
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	_, err = models.ParseFacets("colour")
+	var validationErr models.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "facets", validationErr.Field)
+}
+
+func TestListingService_FacetsAreDisjunctive(t *testing.T) {
+	service, err := newListingService(t)
+	require.NoError(t, err)
+	ctx := context.Background()
+	page := models.PaginationQuery{Page: 1, Limit: 1}
+
+	all, err := service.GetListings(ctx, models.ListingFilter{Facets: "all"}, page)
+	require.NoError(t, err)
+	require.Len(t, all.Facets, len(models.FacetNames))
+	assert.Equal(t, int(all.Meta.Total), sum(facetCounts(all.Facets[models.FacetCity])))
+
+	filtered, err := service.GetListings(ctx, models.ListingFilter{City: []string{"Lagos"}, Facets: "city,property_type"}, page)
+	require.NoError(t, err)
+
+	// The city facet ignores the city filter; the others are narrowed by it
+	assert.Equal(t, facetCounts(all.Facets[models.FacetCity]), facetCounts(filtered.Facets[models.FacetCity]))
+	assert.Equal(t, int(filtered.Meta.Total), sum(facetCounts(filtered.Facets[models.FacetPropertyType])))
+	assert.NotContains(t, filtered.Facets, models.FacetPrice)
+
+	// Sorted by descending count
+	cities := filtered.Facets[models.FacetCity]
+	for i := 1; i < len(cities); i++ {
+		assert.GreaterOrEqual(t, cities[i-1].Count, cities[i].Count)
+	}
+}
+
+func TestListingService_PriceFacetBuckets(t *testing.T) {
+	service, err := newListingService(t)
+	require.NoError(t, err)
+
+	minPrice := 2000000
+	result, err := service.GetListings(context.Background(), models.ListingFilter{MinPrice: &minPrice, Facets: "price,bedrooms"}, models.PaginationQuery{Page: 1, Limit: 1})
+	require.NoError(t, err)
+
+	// Every bucket is listed, in order, with the bounds that select it
+	prices := result.Facets[models.FacetPrice]
+	require.Len(t, prices, 5)
+	assert.Equal(t, "0-999999", prices[0].Value)
+	assert.Equal(t, 0, *prices[0].Min)
+	assert.Equal(t, 999999, *prices[0].Max)
+	assert.Equal(t, "5000000+", prices[4].Value)
+	assert.Nil(t, prices[4].Max)
+	assert.Positive(t, prices[0].Count, "price facet should ignore min_price")
+
+	// Bedrooms honour min_price and are ordered numerically
+	bedrooms := result.Facets[models.FacetBedrooms]
+	assert.Equal(t, int(result.Meta.Total), sum(facetCounts(bedrooms)))
+	for i := 1; i < len(bedrooms); i++ {
+		previous, _ := strconv.Atoi(bedrooms[i-1].Value)
+		current, _ := strconv.Atoi(bedrooms[i].Value)
+		assert.Less(t, previous, current)
+	}
+}
+
+func sum(counts map[string]int) int {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	return total
+}
@@ -0,0 +1,141 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"housing-api/internal/models"
+	"housing-api/pkg/filterexpr"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterExpr_ParsesWithPrecedence(t *testing.T) {
+	node, err := filterexpr.Parse(`(city:Lagos OR city=Abuja) and price<=3000000 AND NOT bedrooms<3 or title~"pool \"deck\""`, models.ListingFilterFields)
+	require.NoError(t, err)
+	assert.Equal(t,
+		`((((city="Lagos" OR city="Abuja") AND price<=3000000) AND NOT bedrooms<3) OR title~"pool \"deck\"")`,
+		node.String())
+}
+
+func TestFilterExpr_ErrorsReportColumn(t *testing.T) {
+	for input, want := range map[string]string{
+		`city:Lagos AND price~3`:        `column 21: operator ~ is not supported for number field price`,
+		`city:Lagos AND colour:red`:     `column 16: unknown field "colour"`,
+		`bedrooms>=three`:               `column 11: field bedrooms expects a number`,
+		`(city:Lagos OR city:Abuja`:     `column 26: expected ), found end of filter`,
+		`city:Lagos city:Abuja`:         `column 12: expected AND, OR or end of filter`,
+		`title~"pool`:                   `column 7: unterminated string`,
+		`city:Lagos & price<1`:          `column 12: unexpected character '&'`,
+		`city:`:                         `column 6: expected a value after city:`,
+		`  `:                            `column 3: empty filter`,
+		`área:x AND price>=1 AND bad:1`: `column 1: unknown field "área"`,
+	} {
+		_, err := filterexpr.Parse(input, models.ListingFilterFields)
+		var exprErr *filterexpr.Error
+		require.ErrorAs(t, err, &exprErr, input)
+		assert.Contains(t, err.Error(), want, input)
+	}
+}
+
+func TestFilterExpr_Eval(t *testing.T) {
+	listing := models.Listing{
+		ID: 7, Title: "Duplex with Pool", Price: "₦2,500,000", Bedrooms: 4, Bathrooms: 3,
+		Location: "Lekki Phase 1, Lagos", Status: []string{"House", "For Rent"},
+	}
+	unpriced := listing
+	unpriced.Price = "Contact agent"
+
+	for input, want := range map[string]bool{
+		`(city:lagos OR city:Abuja) AND price<=3000000 AND bedrooms>=3 AND title~"POOL"`: true,
+		`area:"Lekki Phase 1" AND property_type:house AND listing_type:"for rent"`:       true,
+		`city!=Lagos`:                     false,
+		`NOT (bedrooms>4 OR bathrooms<3)`: true,
+		`id=7 AND price>2500000`:          false,
+	} {
+		node, err := filterexpr.Parse(input, models.ListingFilterFields)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, filterexpr.Eval(node, &listing), input)
+	}
+
+	// A missing price fails every comparison
+	for _, input := range []string{`price<=3000000`, `price!=1`} {
+		node, err := filterexpr.Parse(input, models.ListingFilterFields)
+		require.NoError(t, err)
+		assert.False(t, filterexpr.Eval(node, &unpriced), input)
+	}
+}
+
+func TestFilterExpr_SQL(t *testing.T) {
+	node, err := filterexpr.Parse(`(city:Lagos OR city:Abuja) AND price<=3000000 AND NOT title~"50%_off"`, models.ListingFilterFields)
+	require.NoError(t, err)
+
+	where, args, err := filterexpr.SQL(node, models.ListingFilterColumns)
+	require.NoError(t, err)
+	assert.Equal(t, `((((city IS NOT NULL AND LOWER(city) = LOWER(?)) OR (city IS NOT NULL AND LOWER(city) = LOWER(?))) AND (NULLIF(price_numeric, 0) IS NOT NULL AND NULLIF(price_numeric, 0) <= ?)) AND NOT (title IS NOT NULL AND LOWER(title) LIKE ? ESCAPE '\'))`, where)
+	assert.Equal(t, []any{"Lagos", "Abuja", 3000000.0, `%50\%\_off%`}, args)
+
+	_, _, err = filterexpr.SQL(node, map[string]string{"city": "city"})
+	assert.ErrorContains(t, err, "column 32: field price has no SQL column")
+}
+
+func TestFilterExpr_EvalAgreesWithSQLOnMissingValues(t *testing.T) {
+	unpriced := models.Listing{ID: 1, Price: "Contact agent"}
+	free := models.Listing{ID: 2, Price: "₦0"}
+
+	// Neither listing has a price: Eval finds no value and the SQL column is NULL, so each
+	// comparison is false in both and its negation true
+	for input, want := range map[string]struct {
+		sql     string
+		matches bool
+	}{
+		`price>5`:                 {`(NULLIF(price_numeric, 0) IS NOT NULL AND NULLIF(price_numeric, 0) > ?)`, false},
+		`price=0`:                 {`(NULLIF(price_numeric, 0) IS NOT NULL AND NULLIF(price_numeric, 0) = ?)`, false},
+		`NOT price>5`:             {`NOT (NULLIF(price_numeric, 0) IS NOT NULL AND NULLIF(price_numeric, 0) > ?)`, true},
+		`NOT price!=5 OR id=9`:    {`(NOT (NULLIF(price_numeric, 0) IS NOT NULL AND NULLIF(price_numeric, 0) <> ?) OR (id IS NOT NULL AND id = ?))`, true},
+		`NOT (price<5 AND id>=1)`: {`NOT ((NULLIF(price_numeric, 0) IS NOT NULL AND NULLIF(price_numeric, 0) < ?) AND (id IS NOT NULL AND id >= ?))`, true},
+	} {
+		node, err := filterexpr.Parse(input, models.ListingFilterFields)
+		require.NoError(t, err, input)
+		where, _, err := filterexpr.SQL(node, models.ListingFilterColumns)
+		require.NoError(t, err, input)
+		assert.Equal(t, want.sql, where, input)
+		assert.Equal(t, want.matches, filterexpr.Eval(node, &unpriced), input)
+		assert.Equal(t, want.matches, filterexpr.Eval(node, &free), input)
+	}
+}
+
+func TestListingService_FilterExpression(t *testing.T) {
+	service, err := newListingService(t)
+	require.NoError(t, err)
+	ctx := context.Background()
+	page := models.PaginationQuery{Page: 1, Limit: 100}
+
+	result, err := service.GetListings(ctx, models.ListingFilter{
+		Filter: `(city:Lagos OR city:Abuja) AND price<=3000000 AND bedrooms>=3`,
+	}, page)
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Items)
+	for _, item := range result.Items {
+		listing := item.(models.Listing)
+		assert.Contains(t, []string{"Lagos", "Abuja"}, listing.GetCity())
+		assert.LessOrEqual(t, listing.GetPriceNumeric(), 3000000.0)
+		assert.Greater(t, listing.GetPriceNumeric(), 0.0)
+		assert.GreaterOrEqual(t, listing.Bedrooms, 3)
+	}
+
+	// Combined with the fixed filters
+	narrowed, err := service.GetListings(ctx, models.ListingFilter{
+		Filter: `(city:Lagos OR city:Abuja) AND price<=3000000 AND bedrooms>=3`,
+		City:   []string{"Abuja"},
+	}, page)
+	require.NoError(t, err)
+	assert.Less(t, narrowed.Meta.Total, result.Meta.Total)
+
+	_, err = service.GetListings(ctx, models.ListingFilter{Filter: `price~3`}, page)
+	var validationErr models.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "filter", validationErr.Field)
+	assert.Contains(t, validationErr.Message, "column 6")
+}
@@ -0,0 +1,140 @@
+package unit
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"housing-api/internal/models"
+	"housing-api/pkg/geo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeo_DistanceAndGeohash(t *testing.T) {
+	lekki, maitama := geo.Point{Lat: 6.4698, Lng: 3.5852}, geo.Point{Lat: 9.0882, Lng: 7.4934}
+	assert.InDelta(t, 519.7, geo.DistanceKm(lekki, maitama), 0.1)
+	assert.Zero(t, geo.DistanceKm(lekki, lekki))
+
+	assert.Equal(t, "u4pruydqqvj", geo.Encode(geo.Point{Lat: 57.64911, Lng: 10.40744}, 11))
+	for _, p := range []geo.Point{lekki, maitama, {Lat: -33.86, Lng: 151.21}} {
+		cell := geo.Bounds(geo.Encode(p, 5))
+		assert.True(t, cell.Contains(p))
+		height, width := geo.CellSize(5)
+		assert.InDelta(t, height, cell.MaxLat-cell.MinLat, 1e-9)
+		assert.InDelta(t, width, cell.MaxLng-cell.MinLng, 1e-9)
+	}
+
+	// The box around a circle encloses points at the radius in every direction
+	box := geo.Around(lekki, 10)
+	for _, p := range []geo.Point{{Lat: lekki.Lat + 0.0899, Lng: lekki.Lng}, {Lat: lekki.Lat, Lng: lekki.Lng - 0.0903}} {
+		assert.InDelta(t, 10, geo.DistanceKm(lekki, p), 0.05)
+		assert.True(t, box.Contains(p))
+	}
+}
+
+func TestGeo_GridCandidates(t *testing.T) {
+	points := []geo.Point{
+		{Lat: 6.4478, Lng: 3.4723}, {Lat: 6.4549, Lng: 3.4366}, {Lat: 6.4698, Lng: 3.5852},
+		{Lat: 9.0882, Lng: 7.4934}, {Lat: 89.99, Lng: 179.99},
+	}
+	grid := geo.NewGrid(5)
+	for i, p := range points {
+		grid.Add(i, p)
+	}
+
+	for _, box := range []geo.BoundingBox{
+		geo.Around(points[0], 5), geo.Around(points[0], 20), geo.Around(points[3], 1), geo.World,
+		{MinLat: 89, MinLng: 179, MaxLat: 90, MaxLng: 180},
+	} {
+		candidates := grid.Candidates(box)
+		sort.Ints(candidates)
+		// Every point in the box is a candidate, and none appears twice
+		for i, p := range points {
+			if box.Contains(p) {
+				assert.Contains(t, candidates, i)
+			}
+		}
+		for i := 1; i < len(candidates); i++ {
+			assert.NotEqual(t, candidates[i-1], candidates[i])
+		}
+	}
+	assert.NotContains(t, grid.Candidates(geo.Around(points[0], 5)), 3, "Abuja is not a candidate for a Lagos search")
+	assert.Empty(t, grid.Candidates(geo.BoundingBox{MinLat: 1, MaxLat: 0, MaxLng: 1}))
+}
+
+func TestListingFilter_ParseGeo(t *testing.T) {
+	radius, north := 5.0, 6.5
+	spatial, err := models.ListingFilter{Near: " 6.4478, 3.4723 ", RadiusKm: &radius, MaxLat: &north}.ParseGeo()
+	require.NoError(t, err)
+	assert.Equal(t, &geo.Point{Lat: 6.4478, Lng: 3.4723}, spatial.Near)
+	assert.Equal(t, 5.0, spatial.RadiusKm)
+	assert.Equal(t, &geo.BoundingBox{MinLat: -90, MinLng: -180, MaxLat: 6.5, MaxLng: 180}, spatial.Box)
+
+	zero, south, far := 0.0, 7.0, 200.0
+	for field, filter := range map[string]models.ListingFilter{
+		"near":      {Near: "6.4478"},
+		"radius_km": {RadiusKm: &radius},
+		"max_lng":   {MaxLng: &far},
+		"min_lat":   {MinLat: &south, MaxLat: &north},
+	} {
+		_, err := filter.ParseGeo()
+		var validationErr models.ValidationError
+		require.ErrorAs(t, err, &validationErr, field)
+		assert.Equal(t, field, validationErr.Field)
+	}
+	_, err = models.ListingFilter{Near: "6.4,3.4", RadiusKm: &zero}.ParseGeo()
+	assert.ErrorContains(t, err, "greater than 0")
+	_, err = models.ListingFilter{Near: "96.4,3.4"}.ParseGeo()
+	assert.ErrorContains(t, err, "between -90 and 90")
+}
+
+func TestListingService_RadiusSearch(t *testing.T) {
+	service, err := newListingService(t)
+	require.NoError(t, err)
+	ctx := context.Background()
+	radius := 5.0
+
+	// Around Lekki Phase 1: Banana Island and Ikoyi are within 5 km, Victoria Island isn't. Geocoded
+	// listings share their neighbourhood's centre.
+	result, err := service.GetListings(ctx, models.ListingFilter{Near: "6.4478,3.4723", RadiusKm: &radius},
+		models.PaginationQuery{Page: 1, Limit: 100, Sort: "distance"})
+	require.NoError(t, err)
+	var ids []int
+	previous := 0.0
+	for _, item := range result.Items {
+		listing := item.(models.Listing)
+		ids = append(ids, listing.ID)
+		require.NotNil(t, listing.DistanceKm)
+		assert.LessOrEqual(t, *listing.DistanceKm, radius)
+		assert.GreaterOrEqual(t, *listing.DistanceKm, previous)
+		previous = *listing.DistanceKm
+	}
+	assert.Equal(t, []int{5, 37, 15, 39, 11, 35}, ids)
+
+	// near alone adds distances without limiting
+	all, err := service.GetListings(ctx, models.ListingFilter{Near: "6.4478,3.4723"},
+		models.PaginationQuery{Page: 1, Limit: 100, Sort: "distance"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(40), all.Meta.Total)
+	assert.NotNil(t, all.Items[0].(models.Listing).DistanceKm)
+	assert.NotNil(t, all.Items[len(all.Items)-1].(models.Listing).DistanceKm)
+
+	// A bounding box around Abuja
+	south, north, west, east := 8.9, 9.2, 7.3, 7.6
+	abuja, err := service.GetListings(ctx, models.ListingFilter{MinLat: &south, MaxLat: &north, MinLng: &west, MaxLng: &east},
+		models.PaginationQuery{Page: 1, Limit: 100})
+	require.NoError(t, err)
+	assert.Equal(t, int64(14), abuja.Meta.Total)
+	for _, item := range abuja.Items {
+		listing := item.(models.Listing)
+		assert.Equal(t, "Abuja", listing.GetCity())
+		assert.Nil(t, listing.DistanceKm)
+	}
+
+	_, err = service.GetListings(ctx, models.ListingFilter{}, models.PaginationQuery{Page: 1, Limit: 10, Sort: "distance"})
+	var validationErr models.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "sort", validationErr.Field)
+}
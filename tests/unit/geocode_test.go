@@ -0,0 +1,147 @@
+package unit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"housing-api/internal/config"
+	"housing-api/internal/services"
+	"housing-api/internal/utils"
+	"housing-api/pkg/geo"
+	"housing-api/pkg/geocode"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testGazetteer is a small gazetteer covering two states
+var testGazetteer = geocode.File{States: []geocode.Place{
+	{Name: "Lagos", Aliases: []string{"Lagos State"}, Lat: 6.548, Lng: 3.582, Places: []geocode.Place{
+		{Name: "Lagos", Lat: 6.5244, Lng: 3.3792, Places: []geocode.Place{
+			{Name: "Ikoyi", Lat: 6.4549, Lng: 3.4366},
+			{Name: "Abule Egba", Lat: 6.649, Lng: 3.301},
+			{Name: "Lekki", Lat: 6.4474, Lng: 3.54, Places: []geocode.Place{
+				{Name: "Lekki Phase 1", Aliases: []string{"Lekki Ph 1"}, Lat: 6.4478, Lng: 3.4723},
+				{Name: "Chevron", Lat: 6.4416, Lng: 3.534},
+			}},
+		}},
+	}},
+	{Name: "Federal Capital Territory", Aliases: []string{"FCT"}, Lat: 8.8941, Lng: 7.186, Places: []geocode.Place{
+		{Name: "Abuja", Lat: 9.0765, Lng: 7.3986, Places: []geocode.Place{
+			{Name: "Wuse", Lat: 9.07, Lng: 7.46, Places: []geocode.Place{
+				{Name: "Wuse 2", Aliases: []string{"Wuse II"}, Lat: 9.0765, Lng: 7.47},
+			}},
+			{Name: "Ikoyi Close", Lat: 9.05, Lng: 7.45},
+		}},
+	}},
+}}
+
+func TestGazetteer_Resolve(t *testing.T) {
+	require.NoError(t, testGazetteer.Validate())
+	gazetteer := geocode.NewGazetteer(testGazetteer)
+	assert.Equal(t, 12, gazetteer.Len())
+
+	for _, tc := range []struct {
+		area, city string
+		place      string
+		precision  string
+	}{
+		{"Wuse II", "Abuja", "Wuse 2, Wuse, Abuja, Federal Capital Territory", geocode.PrecisionNeighbourhood},
+		{"lekki ph. 1", "LAGOS", "Lekki Phase 1, Lekki, Lagos, Lagos", geocode.PrecisionNeighbourhood},
+		{"Abule-Egba", "Lagos", "Abule Egba, Lagos, Lagos", geocode.PrecisionNeighbourhood},
+		// The city may be a neighbourhood, and the area a sibling of it
+		{"Chevron", "Lekki", "Chevron, Lekki, Lagos, Lagos", geocode.PrecisionNeighbourhood},
+		{"Ikoyi", "Lekki", "Ikoyi, Lagos, Lagos", geocode.PrecisionNeighbourhood},
+		{"Abuja", "FCT", "Abuja, Federal Capital Territory", geocode.PrecisionCity},
+		// "Lagos" means the city before the state
+		{"Lagos", "Lagos", "Lagos, Lagos", geocode.PrecisionCity},
+		{"Unknownville", "Lagos State", "Lagos", geocode.PrecisionState},
+		{"Unknownville", "Lagos", "Lagos, Lagos", geocode.PrecisionCity},
+		// Unknown cities fall back to an area name unique in the gazetteer
+		{"Ikoyi", "Atlantis", "Ikoyi, Lagos, Lagos", geocode.PrecisionNeighbourhood},
+	} {
+		match, ok := gazetteer.Resolve(tc.area, tc.city)
+		require.True(t, ok, "%s, %s", tc.area, tc.city)
+		assert.Equal(t, tc.place, match.Place, "%s, %s", tc.area, tc.city)
+		assert.Equal(t, tc.precision, match.Precision, "%s, %s", tc.area, tc.city)
+	}
+
+	match, _ := gazetteer.Resolve("Wuse 2", "Abuja")
+	assert.Equal(t, geo.Point{Lat: 9.0765, Lng: 7.47}, match.Point)
+
+	_, ok := gazetteer.Resolve("Unknownville", "Atlantis")
+	assert.False(t, ok)
+	_, ok = (*geocode.Gazetteer)(nil).Resolve("Ikoyi", "Lagos")
+	assert.False(t, ok)
+}
+
+func TestGazetteerFile_Validate(t *testing.T) {
+	err := geocode.File{States: []geocode.Place{
+		{Name: "Lagos", Lat: 6.548, Lng: 3.582, Places: []geocode.Place{
+			{Name: "Ikoyi", Lat: 6.4549, Lng: 3.4366},
+			{Name: "Old Ikoyi", Aliases: []string{"IKOYI"}, Lat: 6.46, Lng: 3.43},
+			{Name: "Yaba"},
+			{Name: " ", Lat: 6.5, Lng: 3.3},
+		}},
+	}}.Validate()
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `"IKOYI" is also a name of states[0] (Lagos).places[0] (Ikoyi)`)
+	assert.ErrorContains(t, err, "(Yaba): lat and lng must locate")
+	assert.ErrorContains(t, err, "places[3]: a place needs a name")
+
+	// Names may repeat under different parents
+	assert.NoError(t, testGazetteer.Validate())
+}
+
+func TestListingService_Geocoding(t *testing.T) {
+	data, err := os.ReadFile(utils.GetDataFilePath("gazetteer.json"))
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "gazetteer.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"states": [{"name": "Lagos", "lat": 6.548, "lng": 3.582}]}`), 0o644))
+
+	cfg, _ := config.Load()
+	cfg.GeocoderGazetteerFile = path
+	service, err := services.NewListingService(cfg)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// Only Lagos is known: Lagos listings take the state's centre, the rest are reported
+	report := service.GetGeocodingReport()
+	assert.Equal(t, 40, report.Total)
+	assert.Equal(t, 10, report.WithCoordinates)
+	assert.NotEmpty(t, report.Unresolved)
+	assert.Equal(t, report.Total, report.WithCoordinates+report.Geocoded[geocode.PrecisionState]+len(report.Unresolved))
+	for _, unresolved := range report.Unresolved {
+		assert.NotContains(t, unresolved.Location, "Lagos")
+	}
+	listing, err := service.GetListingByID(2)
+	require.NoError(t, err)
+	assert.Nil(t, listing.Latitude)
+
+	// The bundled gazetteer resolves every listing; source coordinates are kept
+	modified := service.LastModified()
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+	require.NoError(t, service.ReloadGazetteer(ctx))
+	report = service.GetGeocodingReport()
+	assert.Empty(t, report.Unresolved)
+	assert.True(t, service.LastModified().After(modified))
+	assert.Equal(t, 30, report.Geocoded[geocode.PrecisionNeighbourhood]+report.Geocoded[geocode.PrecisionCity])
+
+	listing, err = service.GetListingByID(28)
+	require.NoError(t, err)
+	require.NotNil(t, listing.Latitude)
+	assert.Equal(t, 9.0765, *listing.Latitude)
+	assert.Equal(t, geocode.PrecisionNeighbourhood, listing.GeoPrecision)
+
+	listing, err = service.GetListingByID(1)
+	require.NoError(t, err)
+	assert.Equal(t, 6.4698, *listing.Latitude)
+	assert.Empty(t, listing.GeoPrecision)
+
+	// An invalid file is rejected and leaves the current gazetteer in place
+	require.NoError(t, os.WriteFile(path, []byte(`{"states": [{"name": "Lagos"}]}`), 0o644))
+	assert.Error(t, service.ReloadGazetteer(ctx))
+	assert.Empty(t, service.GetGeocodingReport().Unresolved)
+}
@@ -0,0 +1,85 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"housing-api/internal/health"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findCheck(report health.Report, name string) *health.CheckResult {
+	for i := range report.Checks {
+		if report.Checks[i].Name == name {
+			return &report.Checks[i]
+		}
+	}
+	return nil
+}
+
+func TestHealth_ReadinessReportsFailingCheck(t *testing.T) {
+	health.Register("unit_ok", func(ctx context.Context) error { return nil })
+	health.Register("unit_broken", func(ctx context.Context) error { return errors.New("dependency down") })
+	defer health.Unregister("unit_ok")
+	defer health.Unregister("unit_broken")
+
+	report := health.Readiness(context.Background())
+
+	assert.Equal(t, health.StatusFail, report.Status)
+
+	ok := findCheck(report, "unit_ok")
+	require.NotNil(t, ok)
+	assert.Equal(t, health.StatusPass, ok.Status)
+
+	broken := findCheck(report, "unit_broken")
+	require.NotNil(t, broken)
+	assert.Equal(t, health.StatusFail, broken.Status)
+	assert.Equal(t, "dependency down", broken.Error)
+	assert.GreaterOrEqual(t, broken.DurationMS, float64(0))
+}
+
+func TestHealth_ReadinessTimesOutSlowCheck(t *testing.T) {
+	health.Register("unit_slow", func(ctx context.Context) error {
+		select {
+		case <-time.After(10 * time.Second):
+		case <-ctx.Done():
+		}
+		return nil
+	})
+	defer health.Unregister("unit_slow")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	report := health.Readiness(ctx)
+
+	slow := findCheck(report, "unit_slow")
+	require.NotNil(t, slow)
+	assert.Equal(t, health.StatusFail, slow.Status)
+	assert.Equal(t, "check timed out", slow.Error)
+}
+
+func TestHealth_ReadinessRecoversPanickingCheck(t *testing.T) {
+	health.Register("unit_panics", func(ctx context.Context) error { panic("nil map") })
+	defer health.Unregister("unit_panics")
+
+	report := health.Readiness(context.Background())
+
+	assert.Equal(t, health.StatusFail, report.Status)
+	panicked := findCheck(report, "unit_panics")
+	require.NotNil(t, panicked)
+	assert.Equal(t, health.StatusFail, panicked.Status)
+	assert.Equal(t, "check panicked: nil map", panicked.Error)
+}
+
+func TestHealth_LivenessIncludesBuildInfo(t *testing.T) {
+	report := health.Liveness()
+
+	assert.Equal(t, health.StatusPass, report.Status)
+	assert.NotEmpty(t, report.Build.Version)
+	assert.NotEmpty(t, report.Build.GoVersion)
+}
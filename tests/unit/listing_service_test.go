@@ -1,17 +1,27 @@
 package unit
 
 import (
+	"context"
+	"math"
 	"testing"
 
+	"housing-api/internal/config"
 	"housing-api/internal/models"
 	"housing-api/internal/services"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+func newListingService(t *testing.T) (*services.ListingService, error) {
+	t.Helper()
+	cfg, _ := config.Load()
+	return services.NewListingService(cfg)
+}
+
 func TestListingService_GetListings(t *testing.T) {
 	// Initialize service
-	service, err := services.NewListingService()
+	service, err := newListingService(t)
 	assert.NoError(t, err)
 	assert.NotNil(t, service)
 
@@ -24,14 +34,14 @@ func TestListingService_GetListings(t *testing.T) {
 
 	filter := models.ListingFilter{}
 
-	result, err := service.GetListings(filter, paginationQuery)
+	result, err := service.GetListings(context.Background(), filter, paginationQuery)
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.LessOrEqual(t, len(result.Items), 10)
 }
 
 func TestListingService_GetListingByID(t *testing.T) {
-	service, err := services.NewListingService()
+	service, err := newListingService(t)
 	assert.NoError(t, err)
 
 	// Test valid ID
@@ -45,3 +55,99 @@ func TestListingService_GetListingByID(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, listing)
 }
+
+func TestListingService_CachesComputedResults(t *testing.T) {
+	service, err := newListingService(t)
+	require.NoError(t, err)
+
+	first, err := service.GetListingStats()
+	require.NoError(t, err)
+	second, err := service.GetListingStats()
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	pq := models.PaginationQuery{Page: 1, Limit: 5}
+	_, err = service.GetListings(context.Background(), models.ListingFilter{City: []string{"Lagos"}}, pq)
+	require.NoError(t, err)
+	_, err = service.GetListings(context.Background(), models.ListingFilter{City: []string{" lagos"}}, pq)
+	require.NoError(t, err)
+
+	stats := service.CacheStats()[0]
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(2), stats.Misses)
+	assert.Equal(t, 2, stats.Entries)
+}
+
+func TestListingService_DoesNotCacheUnserializableFilters(t *testing.T) {
+	service, err := newListingService(t)
+	require.NoError(t, err)
+	ctx := context.Background()
+	nan := math.NaN()
+
+	// A NaN can't be part of a cache key, so these queries must not share one
+	all, err := service.QueryListings(ctx, models.ListingFilter{RadiusKm: &nan}, models.Sort{}, 0, 50)
+	require.NoError(t, err)
+	abuja, err := service.QueryListings(ctx, models.ListingFilter{RadiusKm: &nan, City: []string{"Abuja"}}, models.Sort{}, 0, 50)
+	require.NoError(t, err)
+	assert.NotEqual(t, all.Total, abuja.Total)
+	for _, listing := range abuja.Items {
+		assert.Equal(t, "Abuja", listing.GetCity())
+	}
+	assert.Zero(t, service.CacheStats()[0].Entries)
+}
+
+func TestListingService_CursorPagesShareTotal(t *testing.T) {
+	service, err := newListingService(t)
+	require.NoError(t, err)
+	ctx := context.Background()
+	filter := models.ListingFilter{City: []string{"Lagos"}}
+
+	first, err := service.GetListings(ctx, filter, models.PaginationQuery{Page: 1, Limit: 3})
+	require.NoError(t, err)
+
+	// Each cursor page is cached under its own key; the total is counted once and shared
+	seen := len(first.Items)
+	pages := 0
+	for cursor := first.Meta.NextCursor; cursor != ""; pages++ {
+		page, err := service.GetListings(ctx, filter, models.PaginationQuery{Limit: 3, Cursor: cursor})
+		require.NoError(t, err)
+		assert.Equal(t, first.Meta.Total, page.Meta.Total)
+		seen += len(page.Items)
+		cursor = page.Meta.NextCursor
+	}
+	require.Greater(t, pages, 1)
+	assert.EqualValues(t, first.Meta.Total, seen)
+
+	stats := service.CacheStats()[0]
+	assert.Equal(t, 1+pages+1, stats.Entries)
+	assert.Equal(t, uint64(pages-1), stats.Hits)
+}
+
+func TestListingService_ReloadInvalidatesCache(t *testing.T) {
+	service, err := newListingService(t)
+	require.NoError(t, err)
+
+	_, err = service.GetFiltersMetadata()
+	require.NoError(t, err)
+	require.Equal(t, 1, service.CacheStats()[0].Entries)
+
+	require.NoError(t, service.ReloadListings(context.Background()))
+
+	assert.Equal(t, 0, service.CacheStats()[0].Entries)
+}
+
+func TestListingFilter_NormalizeSplitsValues(t *testing.T) {
+	filter := models.ListingFilter{
+		PropertyType:        []string{"House, Duplex", "Flat"},
+		City:                []string{" ", ""},
+		ExcludePropertyType: []string{"Penthouse,"},
+		Location:            []string{" Lekki, Lagos ", ""},
+	}
+	filter.Normalize()
+
+	assert.Equal(t, []string{"House", "Duplex", "Flat"}, filter.PropertyType)
+	assert.Nil(t, filter.City)
+	assert.Equal(t, []string{"Penthouse"}, filter.ExcludePropertyType)
+	// A location names an area and its city, so it is never split
+	assert.Equal(t, []string{"Lekki, Lagos"}, filter.Location)
+}
@@ -0,0 +1,138 @@
+package unit
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"housing-api/internal/config"
+	"housing-api/internal/middleware/auth"
+	metricsmw "housing-api/internal/middleware/metrics"
+	"housing-api/internal/middleware/ratelimit"
+	"housing-api/internal/repositories"
+	"housing-api/internal/services"
+	"housing-api/pkg/metrics"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scrapeMetric returns the value of the series name{labels} from the metrics endpoint, or 0 when
+// it hasn't been recorded yet. labels are written as exposed, sorted by name.
+func scrapeMetric(t *testing.T, name, labels string) float64 {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	metrics.Handler("").ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	series := name
+	if labels != "" {
+		series += "{" + labels + "}"
+	}
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), series+" "); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			require.NoError(t, err)
+			return parsed
+		}
+	}
+	return 0
+}
+
+func TestMetrics_HandlerRequiresToken(t *testing.T) {
+	handler := metrics.Handler("s3cret")
+
+	for header, want := range map[string]int{
+		"":              http.StatusUnauthorized,
+		"Bearer wrong":  http.StatusUnauthorized,
+		"s3cret":        http.StatusUnauthorized,
+		"Bearer s3cret": http.StatusOK,
+	} {
+		req := httptest.NewRequest("GET", "/metrics", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, want, rec.Code, header)
+	}
+}
+
+func TestMetrics_RecordsRequestsPerRoute(t *testing.T) {
+	app := fiber.New()
+	app.Use(metricsmw.Metrics())
+	app.Get("/unit-metrics/:id", func(c *fiber.Ctx) error {
+		if c.Params("id") == "bad" {
+			return fiber.ErrBadRequest
+		}
+		return c.SendString("ok")
+	})
+
+	ok := `method="GET",route="/unit-metrics/:id",status="200"`
+	bad := `method="GET",route="/unit-metrics/:id",status="400"`
+	unmatched := `method="GET",route="unmatched",status="404"`
+	before := map[string]float64{}
+	for _, labels := range []string{ok, bad, unmatched} {
+		before[labels] = scrapeMetric(t, "housing_api_http_requests_total", labels)
+	}
+	latencyBefore := scrapeMetric(t, "housing_api_http_request_duration_seconds_count", ok)
+
+	for _, path := range []string{"/unit-metrics/1", "/unit-metrics/2", "/unit-metrics/bad", "/unit-metrics-nowhere"} {
+		_, err := app.Test(httptest.NewRequest("GET", path, nil))
+		require.NoError(t, err)
+	}
+
+	// Requests are labelled by route pattern, with errors by the status they turn into
+	assert.Equal(t, before[ok]+2, scrapeMetric(t, "housing_api_http_requests_total", ok))
+	assert.Equal(t, before[bad]+1, scrapeMetric(t, "housing_api_http_requests_total", bad))
+	assert.Equal(t, before[unmatched]+1, scrapeMetric(t, "housing_api_http_requests_total", unmatched))
+	assert.Equal(t, latencyBefore+2, scrapeMetric(t, "housing_api_http_request_duration_seconds_count", ok))
+}
+
+func TestMetrics_CountsRateLimitRejectionsAndAuthFailures(t *testing.T) {
+	cfg, _ := config.Load()
+	cfg.RateLimitMaxRequests = 1
+	cfg.RateLimitWindowMS = time.Minute
+
+	app := fiber.New()
+	app.Use(ratelimit.RateLimiter(cfg, ratelimit.NewStorage()))
+	app.Get("/limited", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	rejections := scrapeMetric(t, "housing_api_rate_limit_rejections_total", "")
+	for _, want := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		resp, err := app.Test(httptest.NewRequest("GET", "/limited", nil))
+		require.NoError(t, err)
+		assert.Equal(t, want, resp.StatusCode)
+	}
+	assert.Equal(t, rejections+1, scrapeMetric(t, "housing_api_rate_limit_rejections_total", ""))
+
+	users, err := repositories.NewUserRepository("")
+	require.NoError(t, err)
+	app = fiber.New()
+	app.Use(auth.JWTMiddleware(services.NewAuthService(cfg, users)))
+	app.Get("/private", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	for header, reason := range map[string]string{
+		"":             "missing_header",
+		"Token abc":    "invalid_format",
+		"Bearer wrong": "invalid_token",
+	} {
+		labels := `reason="` + reason + `"`
+		failures := scrapeMetric(t, "housing_api_auth_failures_total", labels)
+
+		req := httptest.NewRequest("GET", "/private", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, header)
+		assert.Equal(t, failures+1, scrapeMetric(t, "housing_api_auth_failures_total", labels), reason)
+	}
+}
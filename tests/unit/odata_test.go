@@ -0,0 +1,85 @@
+package unit
+
+import (
+	"testing"
+
+	"housing-api/internal/models"
+	"housing-api/pkg/filterexpr"
+	"housing-api/pkg/odata"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOData_TranslatesFilter(t *testing.T) {
+	node, err := odata.ParseFilter(
+		`(City eq 'Lagos' or City eq 'Abuja') and ListPrice le 3000000 and not BedroomsTotal lt 3 or contains(PublicRemarks,'O''Neil')`,
+		models.ResoFilterFields())
+	require.NoError(t, err)
+	assert.Equal(t,
+		`((((city="Lagos" OR city="Abuja") AND price<=3000000) AND NOT bedrooms<3) OR title~"O'Neil")`,
+		node.String())
+
+	// The translation is a valid filter expression over the listing fields
+	_, err = models.ParseFilterExpression(node.String())
+	assert.NoError(t, err)
+}
+
+func TestOData_FilterErrorsReportColumn(t *testing.T) {
+	for input, want := range map[string]string{
+		`City eq 'Lagos' and Colour eq 'red'`: `column 21: unknown or non-filterable property Colour`,
+		`ListingKey eq '1'`:                   `column 1: unknown or non-filterable property ListingKey`,
+		`city eq 'Lagos'`:                     `column 1: unknown or non-filterable property city`,
+		`City gt 'Lagos'`:                     `column 6: operator gt is not supported for string property City`,
+		`BedroomsTotal ge 'three'`:            `column 18: property BedroomsTotal expects a number`,
+		`City eq Lagos`:                       `column 9: property City expects a string literal`,
+		`startswith(City,'La')`:               `column 1: unsupported function startswith`,
+		`contains(ListPrice,'1')`:             `column 10: contains requires a string property`,
+		`(City eq 'Lagos'`:                    `column 17: expected ), found end of $filter`,
+		`City eq 'Lagos`:                      `column 9: unterminated string`,
+		`City eq 'Lagos' City eq 'Abuja'`:     `column 17: expected and, or or end of $filter`,
+	} {
+		_, err := odata.ParseFilter(input, models.ResoFilterFields())
+		var exprErr *filterexpr.Error
+		require.ErrorAs(t, err, &exprErr, input)
+		assert.Contains(t, err.Error(), want, input)
+	}
+}
+
+func TestOData_OrderByAndSelect(t *testing.T) {
+	orderBy, err := odata.ParseOrderBy("ListPrice desc, BedroomsTotal")
+	require.NoError(t, err)
+	assert.Equal(t, []odata.OrderBy{{Property: "ListPrice", Descending: true}, {Property: "BedroomsTotal"}}, orderBy)
+
+	sorting, err := models.ResoSort(orderBy)
+	require.NoError(t, err)
+	assert.Equal(t, "-list_price,bedrooms", sorting.String())
+
+	_, err = odata.ParseOrderBy("ListPrice down")
+	assert.ErrorContains(t, err, "asc or desc")
+	_, err = models.ResoSort([]odata.OrderBy{{Property: "City"}})
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{"ListPrice", "City"}, odata.ParseSelect(" ListPrice , City,"))
+	assert.Nil(t, odata.ParseSelect("*"))
+}
+
+func TestListing_ResoProperty(t *testing.T) {
+	listing := models.Listing{
+		ID: 9, Title: "Serviced Studio", Price: "₦45,000 / night", Bedrooms: 1, Bathrooms: 1,
+		Location: "Victoria Island, Lagos", Status: []string{"Apartment", "Shortlet"},
+	}
+
+	property := listing.ResoProperty(nil)
+	assert.Len(t, property, len(models.ResoPropertyFields))
+	assert.Equal(t, "9", property["ListingKey"])
+	assert.Equal(t, 45000.0, property["ListPrice"])
+	assert.Equal(t, "Daily", property["LeaseAmountFrequency"])
+	assert.Equal(t, "Victoria Island", property["SubdivisionName"])
+	assert.Equal(t, "Lagos", property["City"])
+	assert.Equal(t, "Apartment", property["PropertySubType"])
+
+	listing.Price = "Contact agent"
+	assert.Equal(t, map[string]any{"ListPrice": nil, "LeaseAmountFrequency": "Annually"},
+		listing.ResoProperty([]string{"ListPrice", "LeaseAmountFrequency"}))
+}
@@ -0,0 +1,67 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"housing-api/internal/models"
+	"housing-api/pkg/pagination"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorCodec_RoundTrip(t *testing.T) {
+	codec := pagination.NewCursorCodec("test-secret")
+	cursor := pagination.Cursor{Sort: "id", SortPosition: models.SortPosition{ID: 42}}
+
+	decoded, err := codec.Decode(codec.Encode(cursor), "id")
+	require.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+}
+
+func TestCursorCodec_RejectsTamperedAndForeignCursors(t *testing.T) {
+	codec := pagination.NewCursorCodec("test-secret")
+	token := codec.Encode(pagination.Cursor{Sort: "id", SortPosition: models.SortPosition{ID: 42}})
+
+	forged := pagination.NewCursorCodec("other-secret").Encode(pagination.Cursor{Sort: "id", SortPosition: models.SortPosition{ID: 1}})
+	for name, candidate := range map[string]string{
+		"garbage":     "not-a-cursor",
+		"truncated":   token[:len(token)-2],
+		"forged":      forged,
+		"wrong order": token,
+	} {
+		sort := "id"
+		if name == "wrong order" {
+			sort = "-price"
+		}
+		_, err := codec.Decode(candidate, sort)
+		assert.ErrorIs(t, err, pagination.ErrInvalidCursor, name)
+	}
+}
+
+func TestListingService_CursorPaginationCoversEveryListing(t *testing.T) {
+	service, err := newListingService(t)
+	require.NoError(t, err)
+
+	all, err := service.GetListings(context.Background(), models.ListingFilter{}, models.PaginationQuery{Page: 1, Limit: 100})
+	require.NoError(t, err)
+
+	seen := map[int]bool{}
+	query := models.PaginationQuery{Limit: 3}
+	for {
+		page, err := service.GetListings(context.Background(), models.ListingFilter{}, query)
+		require.NoError(t, err)
+		for _, item := range page.Items {
+			id := item.(models.Listing).ID
+			assert.False(t, seen[id], "listing %d returned twice", id)
+			seen[id] = true
+		}
+		if page.Meta.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.Meta.NextCursor
+	}
+
+	assert.Len(t, seen, int(all.Meta.Total))
+}
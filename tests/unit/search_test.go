@@ -0,0 +1,127 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"housing-api/internal/models"
+	"housing-api/pkg/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyze_FoldsAndStems(t *testing.T) {
+	assert.Equal(t, []string{"ikeja", "duplex"}, search.Analyze("Ìkẹjà DUPLEXES"))
+	assert.Equal(t, search.Analyze("Fully Serviced"), search.Analyze("fully service"))
+	assert.Equal(t, search.Analyze("apartments"), search.Analyze("Apartment"))
+	assert.Equal(t, []string{"4", "bedroom", "semi", "detach"}, search.Analyze("4-Bedroom Semi-Detached"))
+}
+
+func TestIndex_RanksByBM25AndRequiresEveryTerm(t *testing.T) {
+	idx := search.NewIndex(
+		[]search.Field{{Name: "title", Weight: 2}, {Name: "location", Weight: 1}},
+		[]search.Document{
+			{ID: 1, Fields: []string{"Duplex with pool", "Lekki, Lagos"}},
+			{ID: 2, Fields: []string{"Flat near Lekki", "Yaba, Lagos"}},
+			{ID: 3, Fields: []string{"Duplex", "Gwarinpa, Abuja"}},
+		},
+	)
+
+	scores := idx.Search("lekki")
+	require.Len(t, scores, 2)
+	assert.Greater(t, scores[2], scores[1], "title matches outweigh location matches")
+
+	scores = idx.Search("duplex lekki")
+	assert.Equal(t, []int{1}, keys(scores))
+
+	assert.Empty(t, idx.Search("mansion"))
+}
+
+func TestListingService_SearchMatchesTitlesAndCombinesFilters(t *testing.T) {
+	service, err := newListingService(t)
+	require.NoError(t, err)
+
+	// "duplex" appears in titles, not locations
+	result, err := service.SearchListings(context.Background(), "duplexes", models.ListingFilter{}, models.PaginationQuery{Limit: 100})
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Items)
+
+	abuja := 0
+	for _, item := range result.Items {
+		listing := item.(models.Listing)
+		assert.Contains(t, listing.Title, "Duplex")
+		if listing.GetCity() == "Abuja" {
+			abuja++
+		}
+	}
+
+	filtered, err := service.SearchListings(context.Background(), "duplex", models.ListingFilter{City: []string{"Abuja"}}, models.PaginationQuery{Limit: 100})
+	require.NoError(t, err)
+	assert.Len(t, filtered.Items, abuja)
+}
+
+func keys(m map[int]float64) []int {
+	var result []int
+	for k := range m {
+		result = append(result, k)
+	}
+	return result
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 1, search.EditDistance("lekky", "lekki", 2))
+	assert.Equal(t, 1, search.EditDistance("abja", "abuja", 2))
+	assert.Equal(t, 1, search.EditDistance("ikjea", "ikeja", 2), "transposition counts once")
+	assert.Equal(t, 3, search.EditDistance("yaba", "jahi", 2), "capped at max+1")
+}
+
+func TestFuzzyContains(t *testing.T) {
+	assert.True(t, search.FuzzyContains("Victoria Island, Lagos", "Victoria Iland"))
+	assert.True(t, search.FuzzyContains("Lekki Phase 1, Lagos", "lekky"))
+	assert.True(t, search.FuzzyContains("Lekki Phase 1, Lagos", "Phase 1"))
+	assert.False(t, search.FuzzyContains("Yaba, Lagos", "Ikoyi"))
+}
+
+func TestListingService_TypoTolerantSearchAndSuggestions(t *testing.T) {
+	service, err := newListingService(t)
+	require.NoError(t, err)
+
+	exact, err := service.SearchListings(context.Background(), "lekki", models.ListingFilter{}, models.PaginationQuery{Limit: 100})
+	require.NoError(t, err)
+	typo, err := service.SearchListings(context.Background(), "Lekky", models.ListingFilter{}, models.PaginationQuery{Limit: 100})
+	require.NoError(t, err)
+	assert.Equal(t, exact.Meta.Total, typo.Meta.Total)
+	assert.Empty(t, typo.DidYouMean)
+
+	city, err := service.GetListings(context.Background(), models.ListingFilter{City: []string{"Abja"}}, models.PaginationQuery{Limit: 100})
+	require.NoError(t, err)
+	assert.NotZero(t, city.Meta.Total)
+
+	none, err := service.SearchListings(context.Background(), "duplx pol", models.ListingFilter{}, models.PaginationQuery{Limit: 100})
+	require.NoError(t, err)
+	assert.Zero(t, none.Meta.Total)
+	assert.Equal(t, "duplex pool", none.DidYouMean)
+}
+
+func TestPrefixIndex_Complete(t *testing.T) {
+	idx := search.NewPrefixIndex([]search.Completion{
+		{Text: "Lekki Phase 1", Kind: "area", Count: 2},
+		{Text: "Lekki", Kind: "area", Count: 3},
+		{Text: "Lekki", Kind: "city", Count: 2},
+		{Text: "Osapa, Lekki", Kind: "title", Count: 1},
+		{Text: "Lekkis Place", Kind: "title", Count: 9},
+	}, "area", "city", "title")
+
+	completions := idx.Complete("LEK", 10)
+	require.Len(t, completions, 5)
+	assert.Equal(t, search.Completion{Text: "Lekkis Place", Kind: "title", Count: 9}, completions[0])
+	assert.Equal(t, "Osapa, Lekki", completions[4].Text, "mid-text matches rank after leading ones")
+
+	completions = idx.Complete("lekki ", 10)
+	for _, completion := range completions {
+		assert.NotEqual(t, "Lekkis Place", completion.Text)
+	}
+	assert.Len(t, idx.Complete("lekki phase", 10), 1)
+	assert.Empty(t, idx.Complete("ikoyi", 10))
+}
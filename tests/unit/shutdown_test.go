@@ -0,0 +1,75 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"housing-api/pkg/shutdown"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdown_RunsHooksInReverseOrder(t *testing.T) {
+	var order []string
+	for _, name := range []string{"first", "second", "third"} {
+		shutdown.Register(name, func(ctx context.Context) error {
+			order = append(order, name)
+			return nil
+		})
+	}
+	shutdown.Register("failing", func(ctx context.Context) error { return errors.New("disk full") })
+
+	err := shutdown.Run(context.Background())
+	assert.EqualError(t, err, "failing: disk full")
+	assert.Equal(t, []string{"third", "second", "first"}, order)
+
+	// Hooks run once
+	order = nil
+	require.NoError(t, shutdown.Run(context.Background()))
+	assert.Empty(t, order)
+}
+
+func TestShutdown_WaitsForBackgroundWork(t *testing.T) {
+	var written atomic.Bool
+	shutdown.Go(func() {
+		time.Sleep(20 * time.Millisecond)
+		written.Store(true)
+	})
+
+	var sawWrite bool
+	shutdown.Register("flush", func(ctx context.Context) error {
+		sawWrite = written.Load()
+		return nil
+	})
+
+	require.NoError(t, shutdown.Run(context.Background()))
+	assert.True(t, sawWrite, "hooks run after background work finishes")
+}
+
+func TestShutdown_GivesUpAtDeadline(t *testing.T) {
+	release := make(chan struct{})
+	shutdown.Go(func() { <-release })
+
+	var ran bool
+	shutdown.Register("late", func(ctx context.Context) error {
+		ran = true
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := shutdown.Run(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.False(t, ran)
+
+	// Once the work finishes a later run flushes the remaining hooks
+	close(release)
+	require.NoError(t, shutdown.Run(context.Background()))
+	assert.True(t, ran)
+}
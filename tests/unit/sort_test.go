@@ -0,0 +1,85 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"housing-api/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSort(t *testing.T) {
+	sorting, err := models.ParseSort("-price, bedrooms", false)
+	require.NoError(t, err)
+	assert.Equal(t, models.Sort{{Field: "price", Descending: true}, {Field: "bedrooms"}}, sorting)
+	assert.Equal(t, "-price,bedrooms", sorting.String())
+
+	// A trailing ascending id is the implicit tie-breaker
+	sorting, err = models.ParseSort("id", false)
+	require.NoError(t, err)
+	assert.Equal(t, models.DefaultSort, sorting.String())
+}
+
+func TestParseSort_RejectsInvalidFields(t *testing.T) {
+	for spec, search := range map[string]bool{
+		"-colour":         false,
+		"price,-price":    false,
+		"relevance":       false,
+		"bedrooms,floors": true,
+	} {
+		_, err := models.ParseSort(spec, search)
+		var validationErr models.ValidationError
+		require.True(t, errors.As(err, &validationErr), spec)
+		assert.Equal(t, "sort", validationErr.Field)
+	}
+
+	_, err := models.ParseSort("relevance", true)
+	assert.NoError(t, err)
+}
+
+func TestListingService_SortByPriceDescending(t *testing.T) {
+	service, err := newListingService(t)
+	require.NoError(t, err)
+
+	result, err := service.GetListings(context.Background(), models.ListingFilter{}, models.PaginationQuery{Page: 1, Limit: 100, Sort: "-price"})
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Items)
+
+	for i := 1; i < len(result.Items); i++ {
+		prev, cur := result.Items[i-1].(models.Listing), result.Items[i].(models.Listing)
+		if cur.GetNormalizedPrice() > 0 {
+			assert.GreaterOrEqual(t, prev.GetNormalizedPrice(), cur.GetNormalizedPrice())
+		}
+	}
+}
+
+func TestListingService_CursorFollowsSortOrder(t *testing.T) {
+	service, err := newListingService(t)
+	require.NoError(t, err)
+
+	query := models.PaginationQuery{Page: 1, Limit: 100, Sort: "-bedrooms,price"}
+	all, err := service.GetListings(context.Background(), models.ListingFilter{}, query)
+	require.NoError(t, err)
+
+	var walked []interface{}
+	query = models.PaginationQuery{Limit: 4, Sort: "-bedrooms,price"}
+	for {
+		page, err := service.GetListings(context.Background(), models.ListingFilter{}, query)
+		require.NoError(t, err)
+		walked = append(walked, page.Items...)
+		if page.Meta.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.Meta.NextCursor
+	}
+	assert.Equal(t, all.Items, walked)
+
+	// A cursor issued for one sort is rejected under another
+	first, err := service.GetListings(context.Background(), models.ListingFilter{}, models.PaginationQuery{Limit: 4, Sort: "price"})
+	require.NoError(t, err)
+	_, err = service.GetListings(context.Background(), models.ListingFilter{}, models.PaginationQuery{Limit: 4, Sort: "-price", Cursor: first.Meta.NextCursor})
+	assert.Error(t, err)
+}
@@ -0,0 +1,149 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"housing-api/internal/config"
+	"housing-api/internal/models"
+	"housing-api/internal/repositories"
+	"housing-api/internal/services"
+	"housing-api/internal/utils"
+	"housing-api/pkg/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSynonymListingService creates a listing service whose synonyms file is a writable copy of
+// the bundled one
+func newSynonymListingService(t *testing.T) (*services.ListingService, string) {
+	t.Helper()
+	data, err := os.ReadFile(utils.GetDataFilePath("synonyms.json"))
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "synonyms.json")
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	cfg, _ := config.Load()
+	cfg.SearchSynonymsFile = path
+	service, err := services.NewListingService(cfg)
+	require.NoError(t, err)
+	return service, path
+}
+
+func TestSynonyms_Expand(t *testing.T) {
+	synonyms := search.NewSynonyms(search.Dictionary{
+		Synonyms:      [][]string{{"flat", "apartment"}, {"self con", "studio"}},
+		Abbreviations: map[string]string{"vi": "victoria island"},
+	})
+
+	assert.Equal(t, []string{"flat", "apartment"}, synonyms.Expand("Flat"))
+	assert.Equal(t, []string{"apartment", "flat"}, synonyms.Expand("apartment"))
+	assert.Equal(t, []string{"self con lekki", "studio lekki"}, synonyms.Expand("Self Con Lekki"))
+
+	// Abbreviations expand one way only
+	assert.Equal(t, []string{"vi", "victoria island"}, synonyms.Expand("VI"))
+	assert.Equal(t, []string{"victoria island"}, synonyms.Expand("victoria island"))
+
+	var none *search.Synonyms
+	assert.Equal(t, []string{"flat"}, none.Expand("flat"))
+}
+
+func TestDictionary_Validate(t *testing.T) {
+	assert.NoError(t, search.Dictionary{Synonyms: [][]string{{"flat", "apartment"}}}.Validate())
+	assert.Error(t, search.Dictionary{Synonyms: [][]string{{"flat"}}}.Validate())
+	assert.Error(t, search.Dictionary{Abbreviations: map[string]string{"vi": " "}}.Validate())
+}
+
+func TestListingService_SynonymsApplyToSearchAndFilters(t *testing.T) {
+	service, _ := newSynonymListingService(t)
+	ctx := context.Background()
+	page := models.PaginationQuery{Page: 1, Limit: 50}
+
+	result, err := service.SearchListings(ctx, "VI", models.ListingFilter{}, page)
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Items)
+	for _, item := range result.Items {
+		listing := item.(models.Listing)
+		assert.Contains(t, listing.Location, "Victoria Island")
+	}
+
+	result, err = service.SearchListings(ctx, "boys quarters", models.ListingFilter{}, page)
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.Items)
+
+	result, err = service.GetListings(ctx, models.ListingFilter{PropertyType: []string{"Flat"}}, page)
+	require.NoError(t, err)
+	types := map[string]bool{}
+	for _, item := range result.Items {
+		listing := item.(models.Listing)
+		types[listing.GetPropertyType()] = true
+	}
+	assert.Equal(t, map[string]bool{"Flat": true, "Apartment": true}, types)
+}
+
+func TestListingService_UpdateSynonyms(t *testing.T) {
+	service, path := newSynonymListingService(t)
+	ctx := context.Background()
+	page := models.PaginationQuery{Page: 1, Limit: 50}
+
+	result, err := service.GetListings(ctx, models.ListingFilter{PropertyType: []string{"Flat"}}, page)
+	require.NoError(t, err)
+	before := result.Meta.Total
+
+	// Invalid dictionaries are rejected and leave the current one in place
+	err = service.UpdateSynonyms(ctx, search.Dictionary{Synonyms: [][]string{{"flat"}}})
+	var validationErr models.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+
+	// A saved dictionary applies immediately, bypassing cached results and conditional requests
+	modified := service.LastModified()
+	require.NoError(t, service.UpdateSynonyms(ctx, search.Dictionary{Abbreviations: map[string]string{"vi": "victoria island"}}))
+	assert.Empty(t, service.GetSynonyms().Synonyms)
+	assert.True(t, service.LastModified().After(modified))
+
+	result, err = service.GetListings(ctx, models.ListingFilter{PropertyType: []string{"Flat"}}, page)
+	require.NoError(t, err)
+	assert.Less(t, result.Meta.Total, before)
+
+	// Hand edits are picked up on reload
+	require.NoError(t, os.WriteFile(path, []byte(`{"synonyms": [["flat", "apartment"]]}`), 0o644))
+	modified = service.LastModified()
+	require.NoError(t, service.ReloadSynonyms(ctx))
+	assert.Empty(t, service.GetSynonyms().Abbreviations)
+	assert.True(t, service.LastModified().After(modified))
+
+	result, err = service.GetListings(ctx, models.ListingFilter{PropertyType: []string{"Flat"}}, page)
+	require.NoError(t, err)
+	assert.Equal(t, before, result.Meta.Total)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"synonyms": [["flat"]]}`), 0o644))
+	assert.Error(t, service.ReloadSynonyms(ctx))
+	assert.Len(t, service.GetSynonyms().Synonyms, 1)
+}
+
+func TestSynonymRepository_ConcurrentSavesKeepFileAndMemoryInStep(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "synonyms.json")
+	repo, err := repositories.NewSynonymRepository(path)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, repo.Save(search.Dictionary{Abbreviations: map[string]string{"vi": fmt.Sprintf("victoria island %d", i)}}))
+		}(i)
+	}
+	wg.Wait()
+
+	// The last save applied is the one left in the file
+	saved, err := repositories.NewSynonymRepository(path)
+	require.NoError(t, err)
+	assert.Equal(t, repo.Get().Dictionary(), saved.Get().Dictionary())
+}